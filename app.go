@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// appJWTLifetime is below the 10 minutes maximum allowed by GitHub, the issue time is backdated against clock drift.
+const (
+	appJWTLifetime  = 9 * time.Minute
+	appJWTClockSkew = time.Minute
+)
+
+// newClient returns a GitHub client authenticated with the token, using the Enterprise URLs if they are set.
+func newClient(httpClient *http.Client, token, apiURL, uploadURL string) (*github.Client, error) {
+	client := github.NewClient(httpClient).WithAuthToken(token)
+	if apiURL == "" {
+		return client, nil
+	}
+	return client.WithEnterpriseURLs(apiURL, uploadURL)
+}
+
+// parseRSAPrivateKey parses a PKCS #1 or PKCS #8 PEM encoded RSA private key.
+func parseRSAPrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key (%T)", parsed)
+	}
+	return key, nil
+}
+
+// appJWT returns the RS256 signed JSON Web Token which authenticates as the GitHub App.
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appInstallationToken returns an installation access token of the GitHub App.
+// If installationID is 0, the installation is looked up from the repository.
+func appInstallationToken(ctx context.Context, appClient *github.Client, policy RetryPolicy, owner, repo string, installationID int64) (string, error) {
+	if installationID == 0 {
+		var installation *github.Installation
+		if err := policy.do(ctx, func(uint) error {
+			var err error
+			installation, _, err = appClient.Apps.FindRepositoryInstallation(ctx, owner, repo)
+			return err
+		}); err != nil {
+			return "", fmt.Errorf("failed to find the GitHub App installation of %s/%s: %w", owner, repo, err)
+		}
+		installationID = installation.GetID()
+	}
+
+	var token *github.InstallationToken
+	if err := policy.do(ctx, func(uint) error {
+		var err error
+		token, _, err = appClient.Apps.CreateInstallationToken(ctx, installationID, nil)
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to create installation token (%d): %w", installationID, err)
+	}
+	return token.GetToken(), nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	t.Log("Parses PKCS #1 and PKCS #8 keys")
+	{
+		pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		parsed, err := parseRSAPrivateKey(string(pkcs1))
+		require.NoError(t, err)
+		require.True(t, key.Equal(parsed))
+
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		parsed, err = parseRSAPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+		require.NoError(t, err)
+		require.True(t, key.Equal(parsed))
+
+		_, err = parseRSAPrivateKey("not a key")
+		require.EqualError(t, err, "no PEM data found")
+	}
+
+	t.Log("Signs the claims with RS256")
+	{
+		now := time.Unix(1700000000, 0)
+		token, err := appJWT(12345, key, now)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+		require.Len(t, parts, 3)
+
+		claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		var claims map[string]interface{}
+		require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+		require.Equal(t, map[string]interface{}{"iat": float64(1699999940), "exp": float64(1700000540), "iss": "12345"}, claims)
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+	}
+}
+
+func TestAppInstallationToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/installation", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":42}`))
+	})
+	mux.HandleFunc("/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		_, _ = w.Write([]byte(`{"token":"ghs_token"}`))
+	})
+	client := setupTestClient(t, mux, nil)
+
+	t.Log("Looks up the installation of the repository")
+	{
+		token, err := appInstallationToken(context.Background(), client, RetryPolicy{}, "owner", "repo", 0)
+		require.NoError(t, err)
+		require.Equal(t, "ghs_token", token)
+	}
+
+	t.Log("Uses the given installation")
+	{
+		token, err := appInstallationToken(context.Background(), client, RetryPolicy{}, "other", "repo", 42)
+		require.NoError(t, err)
+		require.Equal(t, "ghs_token", token)
+	}
+}
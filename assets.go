@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	assetConflictFail    = "fail"
+	assetConflictSkip    = "skip"
+	assetConflictReplace = "replace"
+	assetConflictRename  = "rename-with-suffix"
+)
+
+// listReleaseAssets returns the assets already attached to the release, keyed by their name.
+func listReleaseAssets(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string, id int64) (map[string]*github.ReleaseAsset, error) {
+	assets := map[string]*github.ReleaseAsset{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var page []*github.ReleaseAsset
+		var resp *github.Response
+		if err := policy.do(ctx, func(uint) error {
+			var err error
+			page, resp, err = client.Repositories.ListReleaseAssets(ctx, owner, repo, id, opts)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list release assets: %w", err)
+		}
+		for _, asset := range page {
+			assets[asset.GetName()] = asset
+		}
+		if resp.NextPage == 0 {
+			return assets, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func deleteReleaseAsset(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string, id int64) error {
+	return policy.do(ctx, func(uint) error {
+		_, err := client.Repositories.DeleteReleaseAsset(ctx, owner, repo, id)
+		return err
+	})
+}
+
+// assetStateUploaded is the state of a release asset whose upload is complete.
+const assetStateUploaded = "uploaded"
+
+// deleteStarterAsset deletes the asset with the given name if it is left in starter state by a failed upload.
+func deleteStarterAsset(fileName string, client *github.Client, owner string, repo string, id int64) error {
+	assets, err := listReleaseAssets(context.Background(), client, RetryPolicy{}, owner, repo, id)
+	if err != nil {
+		return err
+	}
+
+	asset, ok := assets[fileName]
+	if !ok || asset.GetState() != "starter" {
+		return nil
+	}
+	if err := deleteReleaseAsset(context.Background(), client, RetryPolicy{}, owner, repo, asset.GetID()); err != nil {
+		return fmt.Errorf("failed to delete partially uploaded asset (%s): %w", fileName, err)
+	}
+	log.Warnf("Deleted partially uploaded asset: %s", fileName)
+	return nil
+}
+
+// uniqueAssetName appends the lowest numeric suffix to fileName which makes it unique among the existing assets,
+// for example app.apk becomes app-1.apk and app.tar.gz becomes app-1.tar.gz.
+func uniqueAssetName(fileName string, existing map[string]*github.ReleaseAsset) string {
+	ext := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+	if strings.HasSuffix(base, ".tar") {
+		base = strings.TrimSuffix(base, ".tar")
+		ext = ".tar" + ext
+	}
+
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if _, ok := existing[name]; !ok {
+			return name
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueAssetName(t *testing.T) {
+	existing := map[string]*github.ReleaseAsset{
+		"app.apk":      {},
+		"app-1.apk":    {},
+		"app.tar.gz":   {},
+		"CHANGELOG":    {},
+		"app-1.tar.gz": {},
+	}
+
+	require.Equal(t, "app-2.apk", uniqueAssetName("app.apk", existing))
+	require.Equal(t, "app-2.tar.gz", uniqueAssetName("app.tar.gz", existing))
+	require.Equal(t, "CHANGELOG-1", uniqueAssetName("CHANGELOG", existing))
+}
+
+func TestUploadFileListConflictPolicy(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "app.apk")
+	require.NoError(t, os.WriteFile(filePath, []byte("content"), 0600))
+	assets := []releaseAsset{{path: filePath, displayFileName: "app.apk"}}
+
+	setupMux := func(deleted, uploaded *[]string) *http.ServeMux {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/1/assets", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				*uploaded = append(*uploaded, r.URL.Query().Get("name"))
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id":3}`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":2,"name":"app.apk","state":"uploaded"}]`)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases/assets/2", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			*deleted = append(*deleted, "app.apk")
+			w.WriteHeader(http.StatusNoContent)
+		})
+		return mux
+	}
+
+	t.Log("Fail policy returns an error")
+	{
+		var deleted, uploaded []string
+		_, err := uploadFileListWithRetry(assets, assetConflictFail, RetryPolicy{}, setupTestClient(t, setupMux(&deleted, &uploaded), nil), "owner", "repo", 1)
+		require.Error(t, err)
+		require.Empty(t, uploaded)
+	}
+
+	t.Log("Skip policy keeps the existing asset")
+	{
+		var deleted, uploaded []string
+		_, err := uploadFileListWithRetry(assets, assetConflictSkip, RetryPolicy{}, setupTestClient(t, setupMux(&deleted, &uploaded), nil), "owner", "repo", 1)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+		require.Empty(t, uploaded)
+	}
+
+	t.Log("Replace policy deletes the existing asset before uploading")
+	{
+		var deleted, uploaded []string
+		_, err := uploadFileListWithRetry(assets, assetConflictReplace, RetryPolicy{}, setupTestClient(t, setupMux(&deleted, &uploaded), nil), "owner", "repo", 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"app.apk"}, deleted)
+		require.Equal(t, []string{"app.apk"}, uploaded)
+	}
+
+	t.Log("Rename policy uploads the file with a suffix")
+	{
+		var deleted, uploaded []string
+		_, err := uploadFileListWithRetry(assets, assetConflictRename, RetryPolicy{}, setupTestClient(t, setupMux(&deleted, &uploaded), nil), "owner", "repo", 1)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+		require.Equal(t, []string{"app-1.apk"}, uploaded)
+	}
+
+	t.Log("Partially uploaded assets are deleted instead of being treated as conflicts")
+	{
+		var deleted, uploaded []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/1/assets", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				uploaded = append(uploaded, r.URL.Query().Get("name"))
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id":3}`)
+				return
+			}
+			fmt.Fprint(w, `[{"id":2,"name":"app.apk","state":"starter"}]`)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases/assets/2", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			deleted = append(deleted, "app.apk")
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		_, err := uploadFileListWithRetry(assets, assetConflictFail, RetryPolicy{}, setupTestClient(t, mux, nil), "owner", "repo", 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"app.apk"}, deleted)
+		require.Equal(t, []string{"app.apk"}, uploaded)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	changelogVersionHeadingPattern = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?`)
+	changelogLinkReferencePattern  = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+)
+
+// readBodyFile returns the content of the body file, or only the section of the given tag if it is a changelog.
+func readBodyFile(pth string, changelogSection bool, tag string) (string, error) {
+	content, err := os.ReadFile(pth)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body file (%s): %w", pth, err)
+	}
+	if !changelogSection {
+		return string(content), nil
+	}
+
+	section, err := extractChangelogSection(string(content), tag)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", pth, err)
+	}
+	return section, nil
+}
+
+// extractChangelogSection returns the content of the section of a Keep a Changelog formatted changelog
+// which belongs to the given version, for example the lines under the "## [1.2.0] - 2024-01-31" heading.
+// Versions are matched with and without a v prefix.
+func extractChangelogSection(changelog, version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+
+	var section []string
+	var versions []string
+	var inSection, inCodeBlock bool
+	for _, line := range strings.Split(strings.ReplaceAll(changelog, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if !inCodeBlock {
+			if match := changelogVersionHeadingPattern.FindStringSubmatch(line); match != nil {
+				if inSection {
+					break
+				}
+				headingVersion := strings.TrimPrefix(match[1], "v")
+				versions = append(versions, headingVersion)
+				inSection = headingVersion == version
+				continue
+			}
+			if inSection && changelogLinkReferencePattern.MatchString(line) {
+				continue
+			}
+		}
+
+		if inSection {
+			section = append(section, line)
+		}
+	}
+
+	if !inSection {
+		return "", fmt.Errorf("no changelog section found for version %s, available versions: %s", version, strings.Join(versions, ", "))
+	}
+	return strings.TrimSpace(strings.Join(section, "\n")), nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testChangelog = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+- Upsert mode
+
+## [1.2.0] - 2024-01-31
+
+### Added
+- Glob support in files_to_upload
+
+` + "```" + `
+## not a heading
+` + "```" + `
+
+## [v1.1.0] - 2023-12-01
+
+### Fixed
+- Retry uploads
+
+[Unreleased]: https://github.com/owner/repo/compare/1.2.0...HEAD
+[1.2.0]: https://github.com/owner/repo/compare/v1.1.0...1.2.0
+`
+
+func TestExtractChangelogSection(t *testing.T) {
+	t.Log("Extracts the section of the version")
+	{
+		section, err := extractChangelogSection(testChangelog, "1.2.0")
+		require.NoError(t, err)
+		require.Equal(t, "### Added\n- Glob support in files_to_upload\n\n```\n## not a heading\n```", section)
+	}
+
+	t.Log("Matches versions with and without v prefix, and drops link references")
+	{
+		section, err := extractChangelogSection(testChangelog, "1.1.0")
+		require.NoError(t, err)
+		require.Equal(t, "### Fixed\n- Retry uploads", section)
+
+		section, err = extractChangelogSection(testChangelog, "v1.2.0")
+		require.NoError(t, err)
+		require.Contains(t, section, "Glob support")
+	}
+
+	t.Log("Fails if there is no section for the version")
+	{
+		_, err := extractChangelogSection(testChangelog, "2.0.0")
+		require.EqualError(t, err, "no changelog section found for version 2.0.0, available versions: Unreleased, 1.2.0, 1.1.0")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+)
+
+const (
+	checksumNone   = "none"
+	checksumSHA256 = "sha256"
+	checksumSHA512 = "sha512"
+)
+
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case checksumSHA256:
+		return sha256.New(), nil
+	case checksumSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+	}
+}
+
+// fileChecksum returns the hex encoded checksum of the file, streaming its content through the hash.
+func fileChecksum(filePath, algorithm string) (string, error) {
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file (%s): %w", filePath, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Warnf("Failed to close file (%s): %s", filePath, err)
+		}
+	}()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read file (%s): %w", filePath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func defaultChecksumFileName(algorithm string) string {
+	return strings.ToUpper(algorithm) + "SUMS"
+}
+
+// addChecksumAssets computes the checksum of every asset and returns the assets extended with a checksum manifest
+// in the format of sha256sum/sha512sum and, if requested, with a per-file sidecar, all written into dir.
+// The files are hashed in a separate pass before the upload, because the manifest has to exist before it is signed.
+func addChecksumAssets(assets []releaseAsset, algorithm, manifestName string, sidecars bool, dir string) ([]releaseAsset, error) {
+	if len(assets) == 0 {
+		return assets, nil
+	}
+	if manifestName == "" {
+		manifestName = defaultChecksumFileName(algorithm)
+	}
+
+	var manifest strings.Builder
+	var result []releaseAsset
+	for _, asset := range assets {
+		checksum, err := fileChecksum(asset.path, algorithm)
+		if err != nil {
+			return nil, err
+		}
+		line := fmt.Sprintf("%s  %s\n", checksum, asset.displayFileName)
+		manifest.WriteString(line)
+		result = append(result, asset)
+
+		if sidecars {
+			sidecar := releaseAsset{
+				path:            filepath.Join(dir, fmt.Sprintf("%d-%s.%s", len(result), asset.displayFileName, algorithm)),
+				displayFileName: asset.displayFileName + "." + algorithm,
+			}
+			if err := os.WriteFile(sidecar.path, []byte(line), 0600); err != nil {
+				return nil, fmt.Errorf("failed to write checksum file (%s): %w", sidecar.path, err)
+			}
+			result = append(result, sidecar)
+		}
+	}
+
+	manifestAsset := releaseAsset{path: filepath.Join(dir, manifestName), displayFileName: manifestName}
+	if err := os.WriteFile(manifestAsset.path, []byte(manifest.String()), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write checksum file (%s): %w", manifestAsset.path, err)
+	}
+	return append(result, manifestAsset), nil
+}
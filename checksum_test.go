@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddChecksumAssets(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "app.apk")
+	require.NoError(t, os.WriteFile(filePath, []byte("test file content\n"), 0600))
+	assets := []releaseAsset{{path: filePath, displayFileName: "my-app.apk"}}
+
+	t.Log("Writes the manifest and the sidecars in sha256sum format")
+	{
+		checksumDir := t.TempDir()
+		result, err := addChecksumAssets(assets, checksumSHA256, "", true, checksumDir)
+		require.NoError(t, err)
+		require.Equal(t, []string{"my-app.apk", "my-app.apk.sha256", "SHA256SUMS"}, assetNames(result))
+
+		expected := "d90ef1651fd9e7563e1a1450a16bd784e9a7e2d3df0f5a798dfc1bdf70a64aea  my-app.apk\n"
+		for _, asset := range result[1:] {
+			content, err := os.ReadFile(asset.path)
+			require.NoError(t, err)
+			require.Equal(t, expected, string(content))
+		}
+	}
+
+	t.Log("Uses the custom manifest name")
+	{
+		result, err := addChecksumAssets(assets, checksumSHA512, "checksums.txt", false, t.TempDir())
+		require.NoError(t, err)
+		require.Equal(t, []string{"my-app.apk", "checksums.txt"}, assetNames(result))
+	}
+}
+
+func assetNames(assets []releaseAsset) []string {
+	var names []string
+	for _, asset := range assets {
+		names = append(names, asset.displayFileName)
+	}
+	return names
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	conventionalChangelogNone  = "none"
+	conventionalChangelogLocal = "local"
+	conventionalChangelogAPI   = "api"
+)
+
+var (
+	conventionalHeaderPattern   = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	conventionalBreakingPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*(.+)$`)
+)
+
+type gitCommit struct {
+	SHA     string
+	Message string
+}
+
+type conventionalCommit struct {
+	SHA          string
+	Type         string
+	Scope        string
+	Description  string
+	Breaking     bool
+	BreakingNote string
+}
+
+// parseConventionalCommit parses a Conventional Commits (https://www.conventionalcommits.org) message,
+// ok is false if the message does not follow the format.
+func parseConventionalCommit(commit gitCommit) (conventionalCommit, bool) {
+	header, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	match := conventionalHeaderPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return conventionalCommit{}, false
+	}
+
+	c := conventionalCommit{
+		SHA:         commit.SHA,
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Description: match[4],
+		Breaking:    match[3] == "!",
+	}
+	if note := conventionalBreakingPattern.FindStringSubmatch(body); note != nil {
+		c.Breaking = true
+		c.BreakingNote = strings.TrimSpace(note[1])
+	}
+	return c, true
+}
+
+// renderConventionalChangelog returns the Markdown changelog of the breaking changes, features and fixes,
+// other commit types are left out.
+func renderConventionalChangelog(commits []gitCommit) string {
+	var breaking, features, fixes []string
+	for _, commit := range commits {
+		c, ok := parseConventionalCommit(commit)
+		if !ok {
+			continue
+		}
+
+		if c.Breaking {
+			note := c.BreakingNote
+			if note == "" {
+				note = c.Description
+			}
+			breaking = append(breaking, changelogEntry(c.Scope, note, c.SHA))
+		}
+		switch c.Type {
+		case "feat":
+			features = append(features, changelogEntry(c.Scope, c.Description, c.SHA))
+		case "fix":
+			fixes = append(fixes, changelogEntry(c.Scope, c.Description, c.SHA))
+		}
+	}
+
+	var sections []string
+	for _, section := range []struct {
+		title   string
+		entries []string
+	}{
+		{"Breaking Changes", breaking},
+		{"Features", features},
+		{"Fixes", fixes},
+	} {
+		if len(section.entries) > 0 {
+			sections = append(sections, "## "+section.title+"\n\n"+strings.Join(section.entries, "\n"))
+		}
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+func changelogEntry(scope, description, sha string) string {
+	entry := "- "
+	if scope != "" {
+		entry += "**" + scope + ":** "
+	}
+	entry += description
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+	if sha != "" {
+		entry += " (" + sha + ")"
+	}
+	return entry
+}
+
+// localCommits returns the commits of the local checkout between base (exclusive) and head, newest first.
+// If base is empty, the previous tag reachable from head is used, or the whole history if there is none.
+func localCommits(base, head string) ([]gitCommit, error) {
+	if head == "" {
+		head = "HEAD"
+	}
+	if base == "" {
+		if tag, err := command.New("git", "describe", "--tags", "--abbrev=0", head+"^").RunAndReturnTrimmedOutput(); err == nil {
+			base = tag
+		}
+	}
+
+	revisions := head
+	if base != "" {
+		revisions = base + ".." + head
+	}
+	out, err := command.New("git", "log", "--format=%H%x00%B%x1e", revisions).RunAndReturnTrimmedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits (%s): %w", revisions, err)
+	}
+
+	var commits []gitCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimSpace(record), "\x00")
+		if ok {
+			commits = append(commits, gitCommit{SHA: sha, Message: message})
+		}
+	}
+	return commits, nil
+}
+
+// compareCommits returns the commits between base (exclusive) and head using the Compare API, newest first.
+// If base is empty, the tag of the latest release is used.
+func compareCommits(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, base, head string) ([]gitCommit, error) {
+	if base == "" {
+		var latest *github.RepositoryRelease
+		if err := policy.do(ctx, func(uint) error {
+			var resp *github.Response
+			var err error
+			latest, resp, err = client.Repositories.GetLatestRelease(ctx, owner, repo)
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return nonRetryableError{err}
+			}
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to get the latest release, set the previous tag: %w", err)
+		}
+		base = latest.GetTagName()
+	}
+
+	var commits []gitCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var comparison *github.CommitsComparison
+		var resp *github.Response
+		if err := policy.do(ctx, func(uint) error {
+			var err error
+			comparison, resp, err = client.Repositories.CompareCommits(ctx, owner, repo, base, head, opts)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to compare %s...%s: %w", base, head, err)
+		}
+
+		for _, commit := range comparison.Commits {
+			commits = append(commits, gitCommit{SHA: commit.GetSHA(), Message: commit.GetCommit().GetMessage()})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	// The Compare API lists the commits oldest first.
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	for _, tt := range []struct {
+		message string
+		want    conventionalCommit
+		ok      bool
+	}{
+		{"feat: add upsert mode", conventionalCommit{Type: "feat", Description: "add upsert mode"}, true},
+		{"fix(upload): retry on 502\n\nDetails", conventionalCommit{Type: "fix", Scope: "upload", Description: "retry on 502"}, true},
+		{"feat(api)!: drop v3 support", conventionalCommit{Type: "feat", Scope: "api", Description: "drop v3 support", Breaking: true}, true},
+		{"refactor: rename inputs\n\nBREAKING CHANGE: files_to_upload is renamed", conventionalCommit{Type: "refactor", Description: "rename inputs", Breaking: true, BreakingNote: "files_to_upload is renamed"}, true},
+		{"Merge pull request #12 from owner/branch", conventionalCommit{}, false},
+	} {
+		got, ok := parseConventionalCommit(gitCommit{Message: tt.message})
+		require.Equal(t, tt.ok, ok, tt.message)
+		require.Equal(t, tt.want, got, tt.message)
+	}
+}
+
+func TestRenderConventionalChangelog(t *testing.T) {
+	commits := []gitCommit{
+		{SHA: "1111111aaaa", Message: "feat(api)!: drop v3 support"},
+		{SHA: "2222222bbbb", Message: "fix: handle empty body"},
+		{SHA: "3333333cccc", Message: "chore: bump dependencies"},
+		{SHA: "4444444dddd", Message: "feat: glob patterns\n\nBREAKING CHANGE: directories are uploaded recursively"},
+	}
+
+	require.Equal(t, `## Breaking Changes
+
+- **api:** drop v3 support (1111111)
+- directories are uploaded recursively (4444444)
+
+## Features
+
+- **api:** drop v3 support (1111111)
+- glob patterns (4444444)
+
+## Fixes
+
+- handle empty body (2222222)`, renderConventionalChangelog(commits))
+}
+
+func TestCompareCommits(t *testing.T) {
+	t.Log("Compares the tag of the latest release with the head, newest first")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"tag_name":"1.0.0"}`))
+		})
+		mux.HandleFunc("/repos/owner/repo/compare/1.0.0...master", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"commits":[{"sha":"a","commit":{"message":"feat: first"}},{"sha":"b","commit":{"message":"fix: second"}}]}`))
+		})
+		client := setupTestClient(t, mux, nil)
+
+		commits, err := compareCommits(context.Background(), client, RetryPolicy{}, "owner", "repo", "", "master")
+		require.NoError(t, err)
+		require.Equal(t, []gitCommit{{SHA: "b", Message: "fix: second"}, {SHA: "a", Message: "feat: first"}}, commits)
+	}
+}
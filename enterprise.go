@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+const githubHost = "github.com"
+
+// githubAliasHosts are the other hosts of github.com repositories, ssh.github.com serves SSH over port 443.
+var githubAliasHosts = []string{"www." + githubHost, "ssh." + githubHost}
+
+func isGitHubHost(host string) bool {
+	if host == "" || strings.EqualFold(host, githubHost) {
+		return true
+	}
+	for _, alias := range githubAliasHosts {
+		if strings.EqualFold(host, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// enterpriseURLs returns the GitHub Enterprise API and upload URLs of the repository host, the given URLs take precedence.
+// If the upload URL is not given, it is derived from the host of the API URL.
+// Empty URLs are returned for github.com repositories without overrides.
+func enterpriseURLs(host, apiURL, uploadURL string) (string, string, error) {
+	if apiURL == "" {
+		if isGitHubHost(host) {
+			if uploadURL != "" {
+				return "", "", fmt.Errorf("upload_base_url requires api_base_url for %s repositories", githubHost)
+			}
+			return "", "", nil
+		}
+		apiURL = "https://" + host + "/api/v3/"
+	}
+
+	if uploadURL == "" {
+		u, err := url.Parse(apiURL)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid API base URL (%s): %w", apiURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return "", "", fmt.Errorf("invalid API base URL (%s): scheme and host are required", apiURL)
+		}
+		uploadURL = u.Scheme + "://" + u.Host + "/api/uploads/"
+	}
+	return apiURL, uploadURL, nil
+}
+
+// checkEnterpriseAPI calls the zen endpoint to make sure the API base URL points to a GitHub API.
+func checkEnterpriseAPI(ctx context.Context, client *github.Client, policy RetryPolicy) error {
+	if err := policy.do(ctx, func(uint) error {
+		_, _, err := client.Meta.Zen(ctx)
+		return err
+	}); err != nil {
+		return fmt.Errorf("GitHub Enterprise API (%s) is not reachable: %w", client.BaseURL, err)
+	}
+	return nil
+}
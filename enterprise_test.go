@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnterpriseURLs(t *testing.T) {
+	tests := []struct {
+		name          string
+		host          string
+		apiURL        string
+		uploadURL     string
+		wantAPIURL    string
+		wantUploadURL string
+		wantErr       bool
+	}{
+		{name: "github.com", host: "github.com"},
+		{name: "github.com SSH over HTTPS port", host: "ssh.github.com"},
+		{name: "derived from the repository host", host: "github.example.com", wantAPIURL: "https://github.example.com/api/v3/", wantUploadURL: "https://github.example.com/api/uploads/"},
+		{name: "upload URL derived from the API URL", host: "github.example.com", apiURL: "http://api.example.com:8080/api/v3/", wantAPIURL: "http://api.example.com:8080/api/v3/", wantUploadURL: "http://api.example.com:8080/api/uploads/"},
+		{name: "overrides", host: "github.example.com", apiURL: "https://api.example.com/", uploadURL: "https://uploads.example.com/", wantAPIURL: "https://api.example.com/", wantUploadURL: "https://uploads.example.com/"},
+		{name: "upload URL without API URL", host: "github.com", uploadURL: "https://uploads.example.com/", wantErr: true},
+		{name: "API URL without host", host: "github.com", apiURL: "api/v3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiURL, uploadURL, err := enterpriseURLs(tt.host, tt.apiURL, tt.uploadURL)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantAPIURL, apiURL)
+			require.Equal(t, tt.wantUploadURL, uploadURL)
+		})
+	}
+}
+
+func TestCheckEnterpriseAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/zen", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Keep it logically awesome."))
+	})
+	client := setupTestClient(t, mux, nil)
+	require.NoError(t, checkEnterpriseAPI(context.Background(), client, RetryPolicy{}))
+
+	client = setupTestClient(t, http.NewServeMux(), nil)
+	require.Error(t, checkEnterpriseAPI(context.Background(), client, RetryPolicy{}))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var assetNamePlaceholders = []string{"{filename}", "{basename}", "{ext}", "{dir}", "{index}"}
+
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// expandFilePattern returns the regular files matching the given path in lexical order.
+// The path can be a file, a directory (matching every file in it recursively) or a glob pattern,
+// where ** matches any number of directories.
+func expandFilePattern(pattern string) ([]string, error) {
+	if !isGlobPattern(pattern) {
+		info, err := os.Stat(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("file not found (%s): %w", pattern, err)
+		}
+		if !info.IsDir() {
+			return []string{pattern}, nil
+		}
+		files, err := walkFiles(pattern, func(string) bool { return true }, func(string) bool { return true })
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no file found in directory (%s)", pattern)
+		}
+		return files, nil
+	}
+
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	var static []string
+	for len(segments) > 0 && !isGlobPattern(segments[0]) {
+		static, segments = append(static, segments[0]), segments[1:]
+	}
+
+	root := filepath.FromSlash(strings.Join(static, "/"))
+	switch {
+	case len(static) == 1 && static[0] == "":
+		root = string(filepath.Separator)
+	case len(static) == 0:
+		root = "."
+	}
+
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("no file matches pattern (%s): %w", pattern, err)
+	}
+
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern (%s): %w", pattern, err)
+		}
+	}
+
+	var matches []string
+	if containsSegment(segments, "**") {
+		var err error
+		matches, err = walkFiles(root, func(rel string) bool {
+			return matchPathSegments(segments, strings.Split(filepath.ToSlash(rel), "/"))
+		}, func(rel string) bool {
+			return matchPathPrefix(segments, strings.Split(filepath.ToSlash(rel), "/"))
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Without ** the pattern has a fixed depth, there is no need to walk the directories.
+		globMatches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern (%s): %w", pattern, err)
+		}
+		for _, match := range globMatches {
+			if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+				matches = append(matches, match)
+			}
+		}
+		sort.Strings(matches)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no file matches pattern (%s)", pattern)
+	}
+	return matches, nil
+}
+
+// walkFiles returns the regular files under root whose path relative to root is accepted by match, in lexical order,
+// walking only the directories accepted by descend.
+// Symlinks are followed, a directory reached again through a symlink is walked only once,
+// entries which can't be resolved, like dangling symlinks, are skipped.
+func walkFiles(root string, match, descend func(rel string) bool) ([]string, error) {
+	var files []string
+	visited := map[string]bool{}
+
+	var walk func(dir, rel string) error
+	walk = func(dir, rel string) error {
+		realDir, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+		if visited[realDir] {
+			return nil
+		}
+		visited[realDir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			p, entryRel := filepath.Join(dir, entry.Name()), filepath.Join(rel, entry.Name())
+			info, err := os.Stat(p)
+			if err != nil {
+				continue
+			}
+			switch {
+			case info.IsDir() && descend(entryRel):
+				if err := walk(p, entryRel); err != nil {
+					return err
+				}
+			case info.Mode().IsRegular() && match(entryRel):
+				files = append(files, p)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, ""); err != nil {
+		return nil, fmt.Errorf("failed to walk directory (%s): %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func matchPathSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		return matchPathSegments(pattern[1:], segments) || (len(segments) > 0 && matchPathSegments(pattern, segments[1:]))
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchPathSegments(pattern[1:], segments[1:])
+}
+
+// matchPathPrefix reports whether the files under the directory of the given path segments can match the pattern.
+func matchPathPrefix(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return true
+	}
+	if len(segments) == 0 {
+		return true
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchPathPrefix(pattern[1:], segments[1:])
+}
+
+func containsSegment(segments []string, segment string) bool {
+	for _, s := range segments {
+		if s == segment {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAssetNamePlaceholder(nameTemplate string) bool {
+	for _, placeholder := range assetNamePlaceholders {
+		if strings.Contains(nameTemplate, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderAssetName substitutes the placeholders of the name template with the properties of the matched file.
+func renderAssetName(nameTemplate, filePath string, index int) string {
+	fileName := filepath.Base(filePath)
+	ext := filepath.Ext(fileName)
+	return strings.NewReplacer(
+		"{filename}", fileName,
+		"{basename}", strings.TrimSuffix(fileName, ext),
+		"{ext}", ext,
+		"{dir}", filepath.Base(filepath.Dir(filePath)),
+		"{index}", strconv.Itoa(index),
+	).Replace(nameTemplate)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilesListConfig(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"debug/app.apk", "release/app.apk", "release/mapping.txt", "release/nested/extra.apk"} {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, os.WriteFile(p, []byte(name), 0600))
+	}
+
+	t.Log("Expands ** patterns in lexical order")
+	{
+		assets, err := parseFilesListConfig(filepath.Join(dir, "release", "**", "*.apk"))
+		require.NoError(t, err)
+		require.Equal(t, []releaseAsset{
+			{path: filepath.Join(dir, "release", "app.apk"), displayFileName: "app.apk"},
+			{path: filepath.Join(dir, "release", "nested", "extra.apk"), displayFileName: "extra.apk"},
+		}, assets)
+	}
+
+	t.Log("Fails if multiple matches would get the same name")
+	{
+		_, err := parseFilesListConfig(filepath.Join(dir, "**", "*.apk"))
+		require.EqualError(t, err, filepath.Join(dir, "debug", "app.apk")+" and "+filepath.Join(dir, "release", "app.apk")+" would be uploaded with the same name (app.apk), use a custom name for one of them")
+
+		_, err = parseFilesListConfig(filepath.Join(dir, "debug", "app.apk") + "\n" + filepath.Join(dir, "release", "app.apk"))
+		require.Error(t, err)
+	}
+
+	t.Log("Expands directories recursively")
+	{
+		assets, err := parseFilesListConfig(filepath.Join(dir, "release"))
+		require.NoError(t, err)
+		require.Equal(t, 3, len(assets))
+	}
+
+	t.Log("Renders the name template for multiple matches")
+	{
+		assets, err := parseFilesListConfig(filepath.Join(dir, "*", "app.apk") + "|{dir}-{basename}{ext}")
+		require.NoError(t, err)
+		require.Equal(t, []releaseAsset{
+			{path: filepath.Join(dir, "debug", "app.apk"), displayFileName: "debug-app.apk"},
+			{path: filepath.Join(dir, "release", "app.apk"), displayFileName: "release-app.apk"},
+		}, assets)
+	}
+
+	t.Log("Keeps the custom name of a single match")
+	{
+		assets, err := parseFilesListConfig(filepath.Join(dir, "*", "mapping.txt") + "|mapping-release.txt")
+		require.NoError(t, err)
+		require.Equal(t, []releaseAsset{{path: filepath.Join(dir, "release", "mapping.txt"), displayFileName: "mapping-release.txt"}}, assets)
+	}
+
+	t.Log("Fails if multiple matches would get the same custom name")
+	{
+		_, err := parseFilesListConfig(filepath.Join(dir, "*", "app.apk") + "|app.apk")
+		require.Error(t, err)
+	}
+
+	t.Log("Fails if nothing matches the pattern")
+	{
+		_, err := parseFilesListConfig(filepath.Join(dir, "*.ipa"))
+		require.Error(t, err)
+	}
+
+	t.Log("Follows symlinks to files and directories")
+	{
+		linked := filepath.Join(dir, "linked")
+		require.NoError(t, os.MkdirAll(linked, 0700))
+		require.NoError(t, os.Symlink(filepath.Join(dir, "release", "mapping.txt"), filepath.Join(linked, "mapping.txt")))
+		require.NoError(t, os.Symlink(filepath.Join(dir, "debug"), filepath.Join(linked, "debug")))
+		require.NoError(t, os.Symlink(linked, filepath.Join(linked, "loop")))
+
+		assets, err := parseFilesListConfig(linked)
+		require.NoError(t, err)
+		require.Equal(t, []releaseAsset{
+			{path: filepath.Join(linked, "debug", "app.apk"), displayFileName: "app.apk"},
+			{path: filepath.Join(linked, "mapping.txt"), displayFileName: "mapping.txt"},
+		}, assets)
+	}
+
+	t.Log("Skips the entries which can't be resolved")
+	{
+		require.NoError(t, os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "release", "dangling.apk")))
+
+		assets, err := parseFilesListConfig(filepath.Join(dir, "release", "*.apk"))
+		require.NoError(t, err)
+		require.Equal(t, []releaseAsset{{path: filepath.Join(dir, "release", "app.apk"), displayFileName: "app.apk"}}, assets)
+
+		assets, err = parseFilesListConfig(filepath.Join(dir, "release", "**", "extra.apk"))
+		require.NoError(t, err)
+		require.Equal(t, []releaseAsset{{path: filepath.Join(dir, "release", "nested", "extra.apk"), displayFileName: "extra.apk"}}, assets)
+	}
+
+	t.Log("Fails if the directory has no files")
+	{
+		empty := filepath.Join(dir, "empty")
+		require.NoError(t, os.MkdirAll(filepath.Join(empty, "nested"), 0700))
+		_, err := parseFilesListConfig(empty)
+		require.EqualError(t, err, "no file found in directory ("+empty+")")
+	}
+}
+
+func TestMatchPathPrefix(t *testing.T) {
+	require.True(t, matchPathPrefix([]string{"*", "*.apk"}, []string{"release"}))
+	require.False(t, matchPathPrefix([]string{"*", "*.apk"}, []string{"release", "nested"}))
+	require.False(t, matchPathPrefix([]string{"release", "*.apk"}, []string{"debug"}))
+	require.True(t, matchPathPrefix([]string{"release", "**", "*.apk"}, []string{"release", "nested", "deep"}))
+}
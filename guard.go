@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+func listReleases(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string) ([]*github.RepositoryRelease, error) {
+	var all []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var releases []*github.RepositoryRelease
+		var resp *github.Response
+		if err := policy.do(ctx, func(uint) error {
+			var err error
+			releases, resp, err = client.Repositories.ListReleases(ctx, owner, repo, opts)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		all = append(all, releases...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// parsePrefixedVersion parses a semantic version tag which starts with the given prefix.
+func parsePrefixedVersion(tag, prefix string) (semanticVersion, error) {
+	if !strings.HasPrefix(tag, prefix) {
+		return semanticVersion{}, fmt.Errorf("%s does not start with the tag prefix (%s)", tag, prefix)
+	}
+	return parseSemanticVersion(strings.TrimPrefix(tag, prefix))
+}
+
+// checkVersionIncrease fails if the tag of a stable release is not higher than the tags of the existing stable releases.
+// Drafts, pre-releases, the release of the same tag and tags without the prefix are not compared.
+// If allowDecrease is set, the error is only logged as a warning.
+func checkVersionIncrease(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, tag, prefix string, prerelease, allowDecrease bool) error {
+	version, err := parsePrefixedVersion(tag, prefix)
+	if err != nil {
+		return fmt.Errorf("version guard requires a semantic version tag: %w", err)
+	}
+	if prerelease || version.IsPrerelease() {
+		return nil
+	}
+
+	releases, err := listReleases(ctx, client, policy, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	var highest *semanticVersion
+	var highestTag string
+	for _, release := range releases {
+		if release.GetDraft() || release.GetPrerelease() || release.GetTagName() == tag {
+			continue
+		}
+		v, err := parsePrefixedVersion(release.GetTagName(), prefix)
+		if err != nil || v.IsPrerelease() {
+			continue
+		}
+		if highest == nil || v.compare(*highest) > 0 {
+			highest, highestTag = &v, release.GetTagName()
+		}
+	}
+
+	if highest == nil || version.compare(*highest) > 0 {
+		return nil
+	}
+
+	err = fmt.Errorf("%s is not higher than the highest existing release (%s)", tag, highestTag)
+	if allowDecrease {
+		log.Warnf("%s, releasing it anyway", err)
+		return nil
+	}
+	return fmt.Errorf("%w, set allow_version_decrease to yes to release it anyway", err)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckVersionIncrease(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"tag_name":"release-2.0.0","draft":true},
+			{"tag_name":"release-1.6.0-rc.1","prerelease":true},
+			{"tag_name":"release-1.5.0"},
+			{"tag_name":"nightly"},
+			{"tag_name":"release-1.4.0"}
+		]`))
+	})
+	client := setupTestClient(t, mux, nil)
+
+	t.Log("Accepts a higher version")
+	{
+		require.NoError(t, checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "release-1.5.1", "release-", false, false))
+	}
+
+	t.Log("Refuses a lower or equal version")
+	{
+		err := checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "release-1.4.1", "release-", false, false)
+		require.EqualError(t, err, "release-1.4.1 is not higher than the highest existing release (release-1.5.0), set allow_version_decrease to yes to release it anyway")
+	}
+
+	t.Log("Skips the release of the same tag, pre-releases and the override")
+	{
+		require.NoError(t, checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "release-1.5.0", "release-", false, false))
+		require.NoError(t, checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "release-1.4.1", "release-", true, false))
+		require.NoError(t, checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "release-1.4.1", "release-", false, true))
+	}
+
+	t.Log("Fails if the tag does not have the prefix")
+	{
+		err := checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.6.0", "release-", false, false)
+		require.EqualError(t, err, "version guard requires a semantic version tag: 1.6.0 does not start with the tag prefix (release-)")
+	}
+}
+
+func TestCheckVersionIncreaseWithoutPrefix(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"tag_name":"v1.5.0"},{"tag_name":"1.4.0"}]`))
+	})
+	client := setupTestClient(t, mux, nil)
+
+	require.NoError(t, checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.6.0", "", false, false))
+	err := checkVersionIncrease(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.5.0", "", false, false)
+	require.EqualError(t, err, "1.5.0 is not higher than the highest existing release (v1.5.0), set allow_version_decrease to yes to release it anyway")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	usernameCheckNone = "none"
+	usernameCheckWarn = "warn"
+	usernameCheckFail = "fail"
+	usernameCheckBot  = "bot"
+)
+
+// checkAuthenticatedUser compares the username with the login of the user the token belongs to.
+// In warn mode mismatches and failed lookups are only logged.
+func checkAuthenticatedUser(ctx context.Context, client *github.Client, policy RetryPolicy, username, mode string) error {
+	if username == "" || (mode != usernameCheckWarn && mode != usernameCheckFail) {
+		return nil
+	}
+
+	var user *github.User
+	if err := policy.do(ctx, func(uint) error {
+		var err error
+		user, _, err = client.Users.Get(ctx, "")
+		return err
+	}); err != nil {
+		err = fmt.Errorf("failed to get the authenticated user: %w", err)
+		if mode == usernameCheckWarn {
+			log.Warnf("%s", err)
+			return nil
+		}
+		return err
+	}
+
+	if strings.EqualFold(user.GetLogin(), username) {
+		return nil
+	}
+	err := fmt.Errorf("the token belongs to %s, not to %s", user.GetLogin(), username)
+	if mode == usernameCheckWarn {
+		log.Warnf("%s", err)
+		return nil
+	}
+	return err
+}
+
+// checkReleaseAuthor compares the username with the author of the release, used when the step acts as a bot
+// (for example a GitHub App) which can't be looked up before the release is created.
+func checkReleaseAuthor(release *github.RepositoryRelease, username, mode string) error {
+	if username == "" || mode != usernameCheckBot {
+		return nil
+	}
+	if author := release.GetAuthor().GetLogin(); !strings.EqualFold(author, username) {
+		return fmt.Errorf("the release is authored by %s, not by %s", author, username)
+	}
+	return nil
+}
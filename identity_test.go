@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAuthenticatedUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"login":"Octocat"}`))
+	})
+	client := setupTestClient(t, mux, nil)
+
+	t.Log("Matches the login case insensitively")
+	{
+		require.NoError(t, checkAuthenticatedUser(context.Background(), client, RetryPolicy{}, "octocat", usernameCheckFail))
+	}
+
+	t.Log("Fails or warns on mismatch")
+	{
+		err := checkAuthenticatedUser(context.Background(), client, RetryPolicy{}, "hubot", usernameCheckFail)
+		require.EqualError(t, err, "the token belongs to Octocat, not to hubot")
+
+		require.NoError(t, checkAuthenticatedUser(context.Background(), client, RetryPolicy{}, "hubot", usernameCheckWarn))
+	}
+}
+
+func TestCheckReleaseAuthor(t *testing.T) {
+	release := &github.RepositoryRelease{Author: &github.User{Login: github.String("release-app[bot]")}}
+
+	require.NoError(t, checkReleaseAuthor(release, "release-app[bot]", usernameCheckBot))
+	require.EqualError(t, checkReleaseAuthor(release, "octocat", usernameCheckBot), "the release is authored by release-app[bot], not by octocat")
+	require.NoError(t, checkReleaseAuthor(release, "octocat", usernameCheckWarn))
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	makeLatestTrue   = "true"
+	makeLatestFalse  = "false"
+	makeLatestLegacy = "legacy"
+	makeLatestAuto   = "auto"
+)
+
+// getLatestRelease returns the release marked as latest, or nil if there is no such release.
+func getLatestRelease(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string) (*github.RepositoryRelease, error) {
+	var latest *github.RepositoryRelease
+	var notFound bool
+	if err := policy.do(ctx, func(uint) error {
+		var resp *github.Response
+		var err error
+		latest, resp, err = client.Repositories.GetLatestRelease(ctx, owner, repo)
+		if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+			notFound = true
+			return nil
+		}
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get the latest release: %w", err)
+	}
+	if notFound {
+		return nil, nil
+	}
+	return latest, nil
+}
+
+// resolveMakeLatest returns the make_latest value of the release.
+// In auto mode a stable release is marked as latest only if its tag is not lower than the tag of the current latest release.
+func resolveMakeLatest(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, makeLatest, tag string, prerelease bool) (string, error) {
+	if makeLatest != makeLatestAuto {
+		return makeLatest, nil
+	}
+	if prerelease {
+		return makeLatestFalse, nil
+	}
+
+	version, err := parseSemanticVersion(tag)
+	if err != nil {
+		return "", fmt.Errorf("make_latest auto requires a semantic version tag: %w", err)
+	}
+	if version.IsPrerelease() {
+		return makeLatestFalse, nil
+	}
+
+	latest, err := getLatestRelease(ctx, client, policy, owner, repo)
+	if err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return makeLatestTrue, nil
+	}
+
+	latestVersion, err := parseSemanticVersion(latest.GetTagName())
+	if err != nil {
+		log.Warnf("The latest release (%s) is not a semantic version, marking the release as latest", latest.GetTagName())
+		return makeLatestTrue, nil
+	}
+	if version.compare(latestVersion) < 0 {
+		log.Printf("The latest release (%s) is higher than %s, the release is not marked as latest", latest.GetTagName(), tag)
+		return makeLatestFalse, nil
+	}
+	return makeLatestTrue, nil
+}
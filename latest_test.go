@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMakeLatest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"v2.1.0"}`))
+	})
+	client := setupTestClient(t, mux, nil)
+
+	tests := []struct {
+		name       string
+		makeLatest string
+		tag        string
+		prerelease bool
+		want       string
+	}{
+		{name: "explicit value is kept", makeLatest: makeLatestLegacy, tag: "1.0.0", want: makeLatestLegacy},
+		{name: "higher stable version", makeLatest: makeLatestAuto, tag: "v2.2.0", want: makeLatestTrue},
+		{name: "same version", makeLatest: makeLatestAuto, tag: "v2.1.0", want: makeLatestTrue},
+		{name: "hotfix of an older major version", makeLatest: makeLatestAuto, tag: "v1.9.1", want: makeLatestFalse},
+		{name: "semver pre-release", makeLatest: makeLatestAuto, tag: "v3.0.0-rc.1", want: makeLatestFalse},
+		{name: "pre-release flag", makeLatest: makeLatestAuto, tag: "v3.0.0", prerelease: true, want: makeLatestFalse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveMakeLatest(context.Background(), client, RetryPolicy{}, "owner", "repo", tt.makeLatest, tt.tag, tt.prerelease)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Log("The first release is marked as latest")
+	{
+		client := setupTestClient(t, http.NewServeMux(), nil)
+		got, err := resolveMakeLatest(context.Background(), client, RetryPolicy{}, "owner", "repo", makeLatestAuto, "1.0.0", false)
+		require.NoError(t, err)
+		require.Equal(t, makeLatestTrue, got)
+	}
+}
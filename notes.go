@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// generatedNotesField is the template field of the generated release notes,
+// if the body template does not contain it, the notes are appended to the body.
+const generatedNotesField = ".GeneratedReleaseNotes"
+
+// generateNotesOptions extends github.GenerateNotesOptions with the configuration file path.
+type generateNotesOptions struct {
+	TagName               string `json:"tag_name"`
+	TargetCommitish       string `json:"target_commitish,omitempty"`
+	PreviousTagName       string `json:"previous_tag_name,omitempty"`
+	ConfigurationFilePath string `json:"configuration_file_path,omitempty"`
+}
+
+// generateReleaseNotes returns the release notes generated by GitHub from the pull requests merged since the previous tag.
+func generateReleaseNotes(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string, opts generateNotesOptions) (string, error) {
+	notes := new(github.RepositoryReleaseNotes)
+	if err := policy.do(ctx, func(uint) error {
+		req, err := client.NewRequest(http.MethodPost, fmt.Sprintf("repos/%s/%s/releases/generate-notes", owner, repo), opts)
+		if err != nil {
+			return nonRetryableError{err}
+		}
+		_, err = client.Do(ctx, req, notes)
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to generate release notes: %w", err)
+	}
+	return notes.Body, nil
+}
+
+func appendReleaseNotes(body, notes string) string {
+	if strings.TrimSpace(body) == "" {
+		return notes
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + notes
+}
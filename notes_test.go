@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateReleaseNotes(t *testing.T) {
+	t.Log("Sends the previous tag and the configuration file path")
+	{
+		var request map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/generate-notes", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			_, _ = w.Write([]byte(`{"name":"1.1.0","body":"## What's Changed\n* Fix upload"}`))
+		})
+		client := setupTestClient(t, mux, nil)
+
+		notes, err := generateReleaseNotes(context.Background(), client, RetryPolicy{}, "owner", "repo", generateNotesOptions{
+			TagName:               "1.1.0",
+			TargetCommitish:       "master",
+			PreviousTagName:       "1.0.0",
+			ConfigurationFilePath: ".github/release.yml",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "## What's Changed\n* Fix upload", notes)
+		require.Equal(t, map[string]string{
+			"tag_name":                "1.1.0",
+			"target_commitish":        "master",
+			"previous_tag_name":       "1.0.0",
+			"configuration_file_path": ".github/release.yml",
+		}, request)
+	}
+}
+
+func TestAppendReleaseNotes(t *testing.T) {
+	t.Log("Appends the notes after the body")
+	{
+		require.Equal(t, "Custom body\n\nNotes", appendReleaseNotes("Custom body\n", "Notes"))
+	}
+
+	t.Log("Uses the notes if the body is empty")
+	{
+		require.Equal(t, "Notes", appendReleaseNotes("  ", "Notes"))
+	}
+}
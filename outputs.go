@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/go-steputils/tools"
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	releaseIDOutputKey        = "GITHUB_RELEASE_ID"
+	releaseURLOutputKey       = "GITHUB_RELEASE_URL"
+	releaseUploadURLOutputKey = "GITHUB_RELEASE_UPLOAD_URL"
+	releaseTagOutputKey       = "GITHUB_RELEASE_TAG"
+	assetURLsOutputKey        = "GITHUB_RELEASE_ASSET_URLS"
+	assetURLMapOutputKey      = "GITHUB_RELEASE_ASSET_URLS_JSON"
+)
+
+// releaseOutputs returns the step outputs describing the release and the assets uploaded to it.
+// The assets listed in the release take precedence, as the download URLs of assets uploaded to a draft
+// change once it is published.
+func releaseOutputs(release *github.RepositoryRelease, uploaded []*github.ReleaseAsset) (map[string]string, error) {
+	releaseAssets := map[int64]*github.ReleaseAsset{}
+	for _, asset := range release.Assets {
+		releaseAssets[asset.GetID()] = asset
+	}
+
+	var assetURLs []string
+	assetURLMap := map[string]string{}
+	for _, asset := range uploaded {
+		if releaseAsset, ok := releaseAssets[asset.GetID()]; ok {
+			asset = releaseAsset
+		}
+		assetURLs = append(assetURLs, asset.GetBrowserDownloadURL())
+		assetURLMap[asset.GetName()] = asset.GetBrowserDownloadURL()
+	}
+
+	assetURLMapJSON, err := json.Marshal(assetURLMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode asset URLs: %w", err)
+	}
+
+	return map[string]string{
+		releaseIDOutputKey:        strconv.FormatInt(release.GetID(), 10),
+		releaseURLOutputKey:       release.GetHTMLURL(),
+		releaseUploadURLOutputKey: release.GetUploadURL(),
+		releaseTagOutputKey:       release.GetTagName(),
+		assetURLsOutputKey:        strings.Join(assetURLs, "\n"),
+		assetURLMapOutputKey:      string(assetURLMapJSON),
+	}, nil
+}
+
+func exportOutputs(outputs map[string]string) error {
+	keys := make([]string, 0, len(outputs))
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := tools.ExportEnvironmentWithEnvman(key, outputs[key]); err != nil {
+			return fmt.Errorf("failed to export %s: %w", key, err)
+		}
+		log.Printf("%s: %s", key, outputs[key])
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseOutputs(t *testing.T) {
+	release := &github.RepositoryRelease{
+		ID:        github.Int64(1),
+		HTMLURL:   github.String("https://github.com/owner/repo/releases/tag/1.0.0"),
+		UploadURL: github.String("https://uploads.github.com/repos/owner/repo/releases/1/assets{?name,label}"),
+		TagName:   github.String("1.0.0"),
+	}
+	uploaded := []*github.ReleaseAsset{
+		{Name: github.String("app.apk"), BrowserDownloadURL: github.String("https://github.com/owner/repo/releases/download/1.0.0/app.apk")},
+		{Name: github.String("SHA256SUMS"), BrowserDownloadURL: github.String("https://github.com/owner/repo/releases/download/1.0.0/SHA256SUMS")},
+	}
+
+	outputs, err := releaseOutputs(release, uploaded)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"GITHUB_RELEASE_ID":         "1",
+		"GITHUB_RELEASE_URL":        "https://github.com/owner/repo/releases/tag/1.0.0",
+		"GITHUB_RELEASE_UPLOAD_URL": "https://uploads.github.com/repos/owner/repo/releases/1/assets{?name,label}",
+		"GITHUB_RELEASE_TAG":        "1.0.0",
+		"GITHUB_RELEASE_ASSET_URLS": "https://github.com/owner/repo/releases/download/1.0.0/app.apk\nhttps://github.com/owner/repo/releases/download/1.0.0/SHA256SUMS",
+		"GITHUB_RELEASE_ASSET_URLS_JSON": `{"SHA256SUMS":"https://github.com/owner/repo/releases/download/1.0.0/SHA256SUMS",` +
+			`"app.apk":"https://github.com/owner/repo/releases/download/1.0.0/app.apk"}`,
+	}, outputs)
+}
+
+func TestReleaseOutputsOfPublishedDraft(t *testing.T) {
+	release := &github.RepositoryRelease{
+		ID:      github.Int64(1),
+		TagName: github.String("1.0.0"),
+		Assets: []*github.ReleaseAsset{
+			{ID: github.Int64(2), Name: github.String("app.apk"), BrowserDownloadURL: github.String("https://github.com/owner/repo/releases/download/1.0.0/app.apk")},
+		},
+	}
+	uploaded := []*github.ReleaseAsset{
+		{ID: github.Int64(2), Name: github.String("app.apk"), BrowserDownloadURL: github.String("https://github.com/owner/repo/releases/download/untagged-123/app.apk")},
+	}
+
+	outputs, err := releaseOutputs(release, uploaded)
+	require.NoError(t, err)
+	require.Equal(t, "https://github.com/owner/repo/releases/download/1.0.0/app.apk", outputs["GITHUB_RELEASE_ASSET_URLS"])
+	require.Equal(t, `{"app.apk":"https://github.com/owner/repo/releases/download/1.0.0/app.apk"}`, outputs["GITHUB_RELEASE_ASSET_URLS_JSON"])
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// preflightCheck makes sure the token can create releases in the repository before anything is created.
+// The OAuth scopes are only checked for classic personal access tokens, other tokens don't report them.
+func preflightCheck(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string) error {
+	var repository *github.Repository
+	var resp *github.Response
+	if err := policy.do(ctx, func(uint) error {
+		var err error
+		repository, resp, err = client.Repositories.Get(ctx, owner, repo)
+		return err
+	}); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("repository %s/%s not found, check the repository URL and that the token has access to it (classic tokens need the repo scope for private repositories)", owner, repo)
+		}
+		return fmt.Errorf("failed to get repository %s/%s: %w", owner, repo, err)
+	}
+
+	if header, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]; ok {
+		scopes := map[string]bool{}
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			scopes[strings.TrimSpace(scope)] = true
+		}
+		if !scopes["repo"] && (repository.GetPrivate() || !scopes["public_repo"]) {
+			required := "public_repo"
+			if repository.GetPrivate() {
+				required = "repo"
+			}
+			return fmt.Errorf("the token does not have the %s scope required to create releases in %s/%s, its scopes are: %s", required, owner, repo, strings.Join(header, ","))
+		}
+	}
+
+	if permissions := repository.GetPermissions(); permissions != nil && !permissions["push"] && !permissions["admin"] {
+		return fmt.Errorf("the token has no push access to %s/%s, releases can only be created with write access", owner, repo)
+	}
+
+	if repository.GetArchived() {
+		return fmt.Errorf("repository %s/%s is archived, unarchive it to create releases", owner, repo)
+	}
+	return nil
+}
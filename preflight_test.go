@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  string
+		body    string
+		wantErr string
+	}{
+		{name: "classic token with repo scope", scopes: "repo, workflow", body: `{"private":true,"permissions":{"push":true}}`},
+		{name: "fine-grained token", body: `{"private":true,"permissions":{"push":true}}`},
+		{name: "public_repo scope on public repository", scopes: "public_repo", body: `{"permissions":{"admin":true}}`},
+		{name: "public_repo scope on private repository", scopes: "public_repo", body: `{"private":true,"permissions":{"push":true}}`, wantErr: "the token does not have the repo scope required to create releases in owner/repo, its scopes are: public_repo"},
+		{name: "read only access", scopes: "repo", body: `{"permissions":{"pull":true}}`, wantErr: "the token has no push access to owner/repo, releases can only be created with write access"},
+		{name: "archived repository", scopes: "repo", body: `{"archived":true,"permissions":{"push":true}}`, wantErr: "repository owner/repo is archived, unarchive it to create releases"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+				if tt.scopes != "" {
+					w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			})
+			client := setupTestClient(t, mux, nil)
+
+			err := preflightCheck(context.Background(), client, RetryPolicy{}, "owner", "repo")
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Log("Explains a missing repository")
+	{
+		client := setupTestClient(t, http.NewServeMux(), nil)
+		err := preflightCheck(context.Background(), client, RetryPolicy{}, "owner", "repo")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "repository owner/repo not found")
+	}
+}
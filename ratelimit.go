@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	headerRateRemaining = "X-RateLimit-Remaining"
+	headerRateReset     = "X-RateLimit-Reset"
+	headerRetryAfter    = "Retry-After"
+
+	// GitHub asks to wait at least a minute after hitting a secondary rate limit without a Retry-After header.
+	defaultSecondaryRateLimitWait = time.Minute
+	rateLimitResetBuffer          = time.Second
+)
+
+// rateLimitTransport is a http.RoundTripper which waits for the GitHub primary and secondary rate limits to reset,
+// and then resends the request, instead of returning the rate limit error to the caller.
+// Waits longer than maxWait are not done, the rate limit error is returned instead, marked as not retryable.
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	maxWait time.Duration
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	mu           sync.Mutex
+	primaryReset time.Time
+}
+
+func newRateLimitTransport(base http.RoundTripper, maxWait time.Duration) *rateLimitTransport {
+	return &rateLimitTransport{base: base, maxWait: maxWait, sleep: sleepContext}
+}
+
+// RoundTrip ...
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.waitForPrimaryReset(req.Context()); err != nil {
+		return nil, err
+	}
+
+	for {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, reason := rateLimitWait(resp)
+		if wait <= 0 {
+			t.recordPrimaryRate(resp)
+			return resp, nil
+		}
+		if wait > t.maxWait {
+			log.Warnf("%s, the required wait of %s exceeds the maximum wait of %s", reason, wait.Round(time.Second), t.maxWait)
+			// Retrying before the rate limit resets would fail the same way.
+			return nil, nonRetryableError{github.CheckResponse(resp)}
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The request body (e.g. an uploaded file) can not be resent, the caller's retry handles it.
+			return resp, nil
+		}
+
+		log.Warnf("%s, waiting %s until %s", reason, wait.Round(time.Second), time.Now().Add(wait).Format(time.RFC3339))
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		log.Printf("Resuming %s %s", req.Method, req.URL.Path)
+
+		req = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// recordPrimaryRate remembers when the exhausted primary rate limit resets, and waits for that before the next request.
+// The remaining and reset headers are dropped, otherwise the GitHub client would record the exhausted rate
+// and fail the next call on its own without sending it.
+func (t *rateLimitTransport) recordPrimaryRate(resp *http.Response) {
+	if resp.Header.Get(headerRateRemaining) != "0" {
+		return
+	}
+	reset := parseRateReset(resp)
+	if reset.IsZero() {
+		return
+	}
+
+	t.mu.Lock()
+	t.primaryReset = reset
+	t.mu.Unlock()
+	resp.Header.Del(headerRateRemaining)
+	resp.Header.Del(headerRateReset)
+}
+
+func (t *rateLimitTransport) waitForPrimaryReset(ctx context.Context) error {
+	t.mu.Lock()
+	reset := t.primaryReset
+	t.mu.Unlock()
+
+	wait := time.Until(reset)
+	if reset.IsZero() || wait <= 0 {
+		return nil
+	}
+	wait += rateLimitResetBuffer
+	if wait > t.maxWait {
+		return nil
+	}
+
+	log.Warnf("GitHub API rate limit exhausted, waiting %s until %s", wait.Round(time.Second), reset.Format(time.RFC3339))
+	return t.sleep(ctx, wait)
+}
+
+// rateLimitWait returns how long to wait before resending the request of a rate limited response, and the reason of it.
+// The body of a 403 or 429 response is replaced with a buffered copy, the original body is closed.
+func rateLimitWait(resp *http.Response) (time.Duration, string) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, ""
+	}
+
+	// github.CheckResponse replaces the body without closing it, which would leak the cancel of a request timeout.
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	err := github.CheckResponse(resp)
+
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		wait := time.Until(rateLimitErr.Rate.Reset.Time) + rateLimitResetBuffer
+		if wait < rateLimitResetBuffer {
+			wait = rateLimitResetBuffer
+		}
+		return wait, "GitHub API rate limit exceeded"
+	}
+
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseRateLimitErr) && abuseRateLimitErr.RetryAfter != nil {
+		return *abuseRateLimitErr.RetryAfter, "GitHub API secondary rate limit exceeded"
+	}
+	if abuseRateLimitErr != nil || resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultSecondaryRateLimitWait
+		if seconds, err := strconv.Atoi(resp.Header.Get(headerRetryAfter)); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return wait, "GitHub API secondary rate limit exceeded"
+	}
+
+	return 0, ""
+}
+
+func parseRateReset(resp *http.Response) time.Time {
+	seconds, err := strconv.ParseInt(resp.Header.Get(headerRateReset), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
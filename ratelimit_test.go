@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	newTransport := func(maxWait time.Duration, waits *[]time.Duration) *rateLimitTransport {
+		transport := newRateLimitTransport(http.DefaultTransport, maxWait)
+		transport.sleep = func(_ context.Context, d time.Duration) error {
+			*waits = append(*waits, d)
+			return nil
+		}
+		return transport
+	}
+
+	t.Log("Waits for the secondary rate limit and resends the request with its body")
+	{
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit","documentation_url":"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		var waits []time.Duration
+		client := &http.Client{Transport: newTransport(time.Minute, &waits)}
+		resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"tag_name":"1.0.0"}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, []time.Duration{30 * time.Second}, waits)
+		require.Equal(t, []string{`{"tag_name":"1.0.0"}`, `{"tag_name":"1.0.0"}`}, bodies)
+	}
+
+	t.Log("Waits for the primary rate limit reset")
+	{
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		var waits []time.Duration
+		client := &http.Client{Transport: newTransport(time.Minute, &waits)}
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, 1, len(waits))
+		require.True(t, waits[0] > 5*time.Second && waits[0] <= 11*time.Second, waits[0])
+	}
+
+	t.Log("Returns a not retryable rate limit error if the wait exceeds the max wait")
+	{
+		var requests int
+		mux := http.NewServeMux()
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		var waits []time.Duration
+		client := setupTestClient(t, mux, &http.Client{Transport: newTransport(10*time.Second, &waits)})
+		err := RetryPolicy{Retries: 3}.do(context.Background(), func(uint) error {
+			_, _, err := client.Users.Get(context.Background(), "")
+			return err
+		})
+		require.Error(t, err)
+		require.False(t, isRetryableError(err))
+		require.Equal(t, 1, requests)
+		require.Empty(t, waits)
+	}
+
+	t.Log("The GitHub client sends the request after an exhausted primary rate limit")
+	{
+		var requests int
+		mux := http.NewServeMux()
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(1-requests))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10))
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		})
+
+		var waits []time.Duration
+		client := setupTestClient(t, mux, &http.Client{Transport: newTransport(time.Minute, &waits)})
+		for i := 0; i < 2; i++ {
+			_, _, err := client.Users.Get(context.Background(), "")
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, requests)
+		require.Equal(t, 1, len(waits))
+	}
+
+	t.Log("Closes the body of a forbidden response after buffering it")
+	{
+		body := &closeRecordingBody{Reader: strings.NewReader(`{"message":"Resource not accessible by integration"}`)}
+		transport := newRateLimitTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}, Body: body, Request: req}, nil
+		}), time.Minute)
+
+		req, err := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		require.True(t, body.closed)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"message":"Resource not accessible by integration"}`, string(data))
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type closeRecordingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeRecordingBody) Close() error {
+	b.closed = true
+	return nil
+}
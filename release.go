@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+const (
+	releaseModeCreate = "create"
+	releaseModeUpdate = "update"
+	releaseModeUpsert = "upsert"
+)
+
+// findReleaseByTag returns the release of the given tag, or nil if there is no such release.
+// The get-by-tag endpoint does not return draft releases, so those are looked up from the release list.
+func findReleaseByTag(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, tag string) (*github.RepositoryRelease, error) {
+	var release *github.RepositoryRelease
+	var notFound bool
+	if err := policy.do(ctx, func(uint) error {
+		var resp *github.Response
+		var err error
+		release, resp, err = client.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+		if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+			notFound = true
+			return nil
+		}
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get release by tag (%s): %w", tag, err)
+	}
+	if !notFound {
+		return release, nil
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		var releases []*github.RepositoryRelease
+		var resp *github.Response
+		if err := policy.do(ctx, func(uint) error {
+			var err error
+			releases, resp, err = client.Repositories.ListReleases(ctx, owner, repo, opts)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		for _, r := range releases {
+			if r.GetTagName() == tag {
+				return r, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// createRelease creates the release. Creating a release is not idempotent, so before retrying it checks whether
+// a failed attempt created the release, e.g. if only its response was lost.
+func createRelease(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	var newRelease *github.RepositoryRelease
+	if err := policy.do(ctx, func(attempt uint) error {
+		if attempt > 0 {
+			existing, err := findReleaseByTag(ctx, client, RetryPolicy{}, owner, repo, release.GetTagName())
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				log.Warnf("Release of the previous attempt found: %s", existing.GetHTMLURL())
+				newRelease = existing
+				return nil
+			}
+		}
+
+		var err error
+		newRelease, _, err = client.Repositories.CreateRelease(ctx, owner, repo, release)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	return newRelease, nil
+}
+
+func editRelease(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo string, id int64, release *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	var updatedRelease *github.RepositoryRelease
+	if err := policy.do(ctx, func(uint) error {
+		var err error
+		updatedRelease, _, err = client.Repositories.EditRelease(ctx, owner, repo, id, release)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update release (%d): %w", id, err)
+	}
+	return updatedRelease, nil
+}
+
+// releaseEdit returns the fields of the release which are updated on an existing release.
+// The legacy make_latest value is not sent, so that updating a release leaves the latest badge unchanged.
+func releaseEdit(release *github.RepositoryRelease) *github.RepositoryRelease {
+	edit := &github.RepositoryRelease{
+		Name:       release.Name,
+		Body:       release.Body,
+		Draft:      release.Draft,
+		Prerelease: release.Prerelease,
+	}
+	if release.GetMakeLatest() != makeLatestLegacy {
+		edit.MakeLatest = release.MakeLatest
+	}
+	return edit
+}
+
+// publishRelease creates or updates the release of release.TagName according to the given release mode.
+// The returned bool reports whether a new release was created.
+// When staged, a new release is created as a draft and an existing release is left unchanged,
+// the releaseEdit of the release is expected to be applied once the assets are uploaded.
+func publishRelease(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, mode string, release *github.RepositoryRelease, staged bool) (*github.RepositoryRelease, bool, error) {
+	edit := releaseEdit(release)
+	if staged {
+		draft := *release
+		draft.Draft = github.Bool(true)
+		release = &draft
+	}
+
+	if mode == "" || mode == releaseModeCreate {
+		newRelease, err := createRelease(ctx, client, policy, owner, repo, release)
+		if err != nil {
+			return nil, false, err
+		}
+		return newRelease, true, nil
+	}
+
+	existing, err := findReleaseByTag(ctx, client, policy, owner, repo, release.GetTagName())
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing == nil {
+		if mode == releaseModeUpdate {
+			return nil, false, fmt.Errorf("no release found for tag (%s), set release_mode to upsert to create it", release.GetTagName())
+		}
+		newRelease, err := createRelease(ctx, client, policy, owner, repo, release)
+		if err != nil {
+			return nil, false, err
+		}
+		return newRelease, true, nil
+	}
+	if staged {
+		return existing, false, nil
+	}
+
+	updatedRelease, err := editRelease(ctx, client, policy, owner, repo, existing.GetID(), edit)
+	if err != nil {
+		return nil, false, err
+	}
+	return updatedRelease, false, nil
+}
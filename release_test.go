@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestClient returns a client of a test server serving mux, sending the requests with httpClient if it is not nil.
+func setupTestClient(t *testing.T, mux *http.ServeMux, httpClient *http.Client) *github.Client {
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(httpClient)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+	client.UploadURL = baseURL
+	return client
+}
+
+func TestPublishRelease(t *testing.T) {
+	t.Log("Upsert updates the existing draft release of the tag")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			fmt.Fprint(w, `[{"id":1,"tag_name":"0.9.0"},{"id":2,"tag_name":"1.0.0","draft":true}]`)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases/2", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPatch, r.Method)
+			var edit github.RepositoryRelease
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&edit))
+			require.Equal(t, "new name", edit.GetName())
+			fmt.Fprint(w, `{"id":2,"tag_name":"1.0.0","name":"new name"}`)
+		})
+
+		release := &github.RepositoryRelease{TagName: github.String("1.0.0"), Name: github.String("new name")}
+		newRelease, created, err := publishRelease(context.Background(), setupTestClient(t, mux, nil), RetryPolicy{}, "owner", "repo", releaseModeUpsert, release, false)
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, int64(2), newRelease.GetID())
+	}
+
+	t.Log("Staged upsert leaves the existing release unchanged")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			fmt.Fprint(w, `{"id":2,"tag_name":"1.0.0","name":"old name"}`)
+		})
+
+		release := &github.RepositoryRelease{TagName: github.String("1.0.0"), Name: github.String("new name")}
+		newRelease, created, err := publishRelease(context.Background(), setupTestClient(t, mux, nil), RetryPolicy{}, "owner", "repo", releaseModeUpsert, release, true)
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, "old name", newRelease.GetName())
+	}
+
+	t.Log("Upsert creates the release if there is none for the tag")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id":3,"tag_name":"1.0.0"}`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		})
+
+		release := &github.RepositoryRelease{TagName: github.String("1.0.0")}
+		newRelease, created, err := publishRelease(context.Background(), setupTestClient(t, mux, nil), RetryPolicy{}, "owner", "repo", releaseModeUpsert, release, false)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, int64(3), newRelease.GetID())
+	}
+
+	t.Log("Create does not create the release again if the response of the first attempt was lost")
+	{
+		var posts int
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				posts++
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			if posts > 0 {
+				fmt.Fprint(w, `[{"id":3,"tag_name":"1.0.0","draft":true}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		})
+
+		release := &github.RepositoryRelease{TagName: github.String("1.0.0")}
+		newRelease, created, err := publishRelease(context.Background(), setupTestClient(t, mux, nil), RetryPolicy{Retries: 2}, "owner", "repo", releaseModeCreate, release, false)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, int64(3), newRelease.GetID())
+		require.Equal(t, 1, posts)
+	}
+
+	t.Log("Update fails if there is no release for the tag")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+		mux.HandleFunc("/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		})
+
+		release := &github.RepositoryRelease{TagName: github.String("1.0.0")}
+		_, _, err := publishRelease(context.Background(), setupTestClient(t, mux, nil), RetryPolicy{}, "owner", "repo", releaseModeUpdate, release, false)
+		require.EqualError(t, err, "no release found for tag (1.0.0), set release_mode to upsert to create it")
+	}
+}
+
+func TestReleaseEdit(t *testing.T) {
+	t.Log("Leaves the latest badge unchanged in legacy mode")
+	{
+		edit := releaseEdit(&github.RepositoryRelease{Name: github.String("1.0.1"), MakeLatest: github.String(makeLatestLegacy)})
+		require.Nil(t, edit.MakeLatest)
+		require.Equal(t, "1.0.1", edit.GetName())
+	}
+
+	t.Log("Sends the explicit make_latest value")
+	{
+		edit := releaseEdit(&github.RepositoryRelease{MakeLatest: github.String(makeLatestFalse)})
+		require.Equal(t, makeLatestFalse, edit.GetMakeLatest())
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+// RetryPolicy describes how failed GitHub API calls are retried:
+// the delay before the nth retry is BaseDelay * 2^(n-1), capped at MaxDelay,
+// and randomized by +/- Jitter times the delay.
+type RetryPolicy struct {
+	Retries   uint
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Jitter    float64
+}
+
+func (p RetryPolicy) delay(retry uint) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// nonRetryableError marks an error which is not worth retrying.
+type nonRetryableError struct {
+	err error
+}
+
+func (e nonRetryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e nonRetryableError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableError reports whether a failed GitHub API call can succeed when retried.
+// Rate limit errors, server errors and errors without a response (network errors) are retryable,
+// other error responses (e.g. 401, 404 or 422) are not.
+func isRetryableError(err error) bool {
+	var nonRetryableErr nonRetryableError
+	if errors.As(err, &nonRetryableErr) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseRateLimitErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		return true
+	}
+
+	var errorResponse *github.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		if errorResponse.Response == nil {
+			return false
+		}
+		statusCode := errorResponse.Response.StatusCode
+		return statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestTimeout
+	}
+
+	return true
+}
+
+// do calls action until it succeeds, fails with an error which is not retryable or the retries are exhausted.
+func (p RetryPolicy) do(ctx context.Context, action func(attempt uint) error) error {
+	for attempt := uint(0); ; attempt++ {
+		err := action(attempt)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.Retries || !isRetryableError(err) {
+			return err
+		}
+
+		log.Warnf("%d. attempt failed: %s", attempt+1, err)
+		delay := p.delay(attempt + 1)
+		log.Debugf("Retrying in %s", delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
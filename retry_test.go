@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	errorResponse := func(statusCode int) error {
+		return fmt.Errorf("failed to create release: %w", &github.ErrorResponse{Response: &http.Response{StatusCode: statusCode}})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "network error", err: fmt.Errorf("connection reset by peer"), want: true},
+		{name: "server error", err: errorResponse(http.StatusBadGateway), want: true},
+		{name: "too many requests", err: errorResponse(http.StatusTooManyRequests), want: true},
+		{name: "rate limit", err: &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}, want: true},
+		{name: "secondary rate limit", err: &github.AbuseRateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}, want: true},
+		{name: "unauthorized", err: errorResponse(http.StatusUnauthorized), want: false},
+		{name: "not found", err: errorResponse(http.StatusNotFound), want: false},
+		{name: "validation failed", err: errorResponse(http.StatusUnprocessableEntity), want: false},
+		{name: "non retryable", err: nonRetryableError{fmt.Errorf("failed to open file")}, want: false},
+		{name: "canceled", err: fmt.Errorf("upload: %w", context.Canceled), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isRetryableError(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Log("Delay grows exponentially up to the max delay")
+	{
+		policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+		require.Equal(t, time.Second, policy.delay(1))
+		require.Equal(t, 2*time.Second, policy.delay(2))
+		require.Equal(t, 4*time.Second, policy.delay(3))
+		require.Equal(t, 5*time.Second, policy.delay(4))
+	}
+
+	t.Log("Jitter keeps the delay within bounds")
+	{
+		policy := RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			delay := policy.delay(1)
+			require.True(t, delay >= 500*time.Millisecond && delay <= 1500*time.Millisecond, delay)
+		}
+	}
+
+	t.Log("Stops at the first non retryable error")
+	{
+		var attempts int
+		err := RetryPolicy{Retries: 3, BaseDelay: time.Millisecond}.do(context.Background(), func(uint) error {
+			attempts++
+			return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, attempts)
+	}
+
+	t.Log("Retries retryable errors until the retries are exhausted")
+	{
+		var attempts int
+		err := RetryPolicy{Retries: 3, BaseDelay: time.Millisecond}.do(context.Background(), func(uint) error {
+			attempts++
+			return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+		})
+		require.Error(t, err)
+		require.Equal(t, 4, attempts)
+	}
+}
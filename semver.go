@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var semanticVersionPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+type semanticVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// parseSemanticVersion parses a semantic version (https://semver.org) tag, with an optional v prefix.
+func parseSemanticVersion(tag string) (semanticVersion, error) {
+	match := semanticVersionPattern.FindStringSubmatch(strings.TrimPrefix(tag, "v"))
+	if match == nil {
+		return semanticVersion{}, fmt.Errorf("%s is not a semantic version", tag)
+	}
+
+	var v semanticVersion
+	var err error
+	if v.Major, err = strconv.Atoi(match[1]); err != nil {
+		return semanticVersion{}, err
+	}
+	if v.Minor, err = strconv.Atoi(match[2]); err != nil {
+		return semanticVersion{}, err
+	}
+	if v.Patch, err = strconv.Atoi(match[3]); err != nil {
+		return semanticVersion{}, err
+	}
+	v.Prerelease = match[4]
+	v.Build = match[5]
+	return v, nil
+}
+
+// IsPrerelease reports whether the version has a pre-release component, like 2.1.0-rc.1.
+func (v semanticVersion) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+func (v semanticVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// compare returns -1, 0 or 1 if v has lower, equal or higher precedence than other, build metadata is ignored.
+func (v semanticVersion) compare(other semanticVersion) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+
+	ids, otherIDs := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(ids) && i < len(otherIDs); i++ {
+		if c := comparePrereleaseIdentifier(ids[i], otherIDs[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ids) < len(otherIDs):
+		return -1
+	case len(ids) > len(otherIDs):
+		return 1
+	}
+	return 0
+}
+
+// comparePrereleaseIdentifier compares numeric identifiers numerically and others lexically,
+// numeric identifiers have lower precedence than alphanumeric ones.
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an < bn {
+			return -1
+		} else if an > bn {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// inferReleaseInfo returns the pre-release flag and the name of the release.
+// A preRelease value of auto and an empty name are inferred from the tag, which has to be a semantic version then.
+func inferReleaseInfo(tag, preRelease, name string) (bool, string, error) {
+	isPreRelease := preRelease == "yes"
+	if preRelease != "auto" && name != "" {
+		return isPreRelease, name, nil
+	}
+
+	version, err := parseSemanticVersion(tag)
+	if err != nil {
+		return false, "", fmt.Errorf("the pre-release flag and the name can only be inferred from semantic version tags: %w", err)
+	}
+	if preRelease == "auto" {
+		isPreRelease = version.IsPrerelease()
+	}
+	if name == "" {
+		name = version.String()
+	}
+	return isPreRelease, name, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSemanticVersion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    semanticVersion
+		wantErr bool
+	}{
+		{tag: "1.2.3", want: semanticVersion{Major: 1, Minor: 2, Patch: 3}},
+		{tag: "v2.1.0-rc.1", want: semanticVersion{Major: 2, Minor: 1, Patch: 0, Prerelease: "rc.1"}},
+		{tag: "1.0.0-beta+exp.sha.5114f85", want: semanticVersion{Major: 1, Prerelease: "beta", Build: "exp.sha.5114f85"}},
+		{tag: "1.2", wantErr: true},
+		{tag: "01.2.3", wantErr: true},
+		{tag: "release-1.2.3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := parseSemanticVersion(tt.tag)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSemanticVersionCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := range ordered {
+		for j := range ordered {
+			a, err := parseSemanticVersion(ordered[i])
+			require.NoError(t, err)
+			b, err := parseSemanticVersion(ordered[j])
+			require.NoError(t, err)
+
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			require.Equal(t, want, a.compare(b), "%s <=> %s", ordered[i], ordered[j])
+		}
+	}
+
+	t.Log("Build metadata is ignored")
+	{
+		a, err := parseSemanticVersion("1.0.0+build.1")
+		require.NoError(t, err)
+		b, err := parseSemanticVersion("v1.0.0+build.2")
+		require.NoError(t, err)
+		require.Equal(t, 0, a.compare(b))
+	}
+}
+
+func TestInferReleaseInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		tag            string
+		preRelease     string
+		releaseName    string
+		wantPreRelease bool
+		wantName       string
+		wantErr        string
+	}{
+		{name: "explicit values", tag: "nightly", preRelease: "yes", releaseName: "Nightly", wantPreRelease: true, wantName: "Nightly"},
+		{name: "auto pre-release", tag: "v2.1.0-rc.1", preRelease: "auto", releaseName: "RC", wantPreRelease: true, wantName: "RC"},
+		{name: "auto stable release", tag: "v2.1.0", preRelease: "auto", releaseName: "Stable", wantName: "Stable"},
+		{name: "name from the version", tag: "v2.1.0-rc.1+build.5", preRelease: "no", wantName: "2.1.0-rc.1+build.5"},
+		{name: "auto with non semver tag", tag: "nightly", preRelease: "auto", releaseName: "Nightly", wantErr: "the pre-release flag and the name can only be inferred from semantic version tags: nightly is not a semantic version"},
+		{name: "empty name with non semver tag", tag: "nightly", preRelease: "no", wantErr: "the pre-release flag and the name can only be inferred from semantic version tags: nightly is not a semantic version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isPreRelease, name, err := inferReleaseInfo(tt.tag, tt.preRelease, tt.releaseName)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPreRelease, isPreRelease)
+			require.Equal(t, tt.wantName, name)
+		})
+	}
+}
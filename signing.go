@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"aead.dev/minisign"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/bitrise-io/go-utils/log"
+)
+
+const (
+	signingNone     = "none"
+	signingOpenPGP  = "openpgp"
+	signingMinisign = "minisign"
+)
+
+// AssetSigner writes the detached signature of the message, the content of the named asset, to w.
+type AssetSigner func(w io.Writer, message io.Reader, fileName string) error
+
+// Signer holds an AssetSigner and the file extension of the signatures it produces
+type Signer struct {
+	assetSigner AssetSigner
+	extension   string
+}
+
+// GetSigner returns the Signer of the given signing method
+func GetSigner(method, key, passphrase string) (*Signer, error) {
+	switch method {
+	case signingOpenPGP:
+		assetSigner, err := newOpenPGPSigner(key, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return &Signer{assetSigner: assetSigner, extension: ".asc"}, nil
+	case signingMinisign:
+		assetSigner, err := newMinisignSigner(key, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return &Signer{assetSigner: assetSigner, extension: ".minisig"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing method: %s", method)
+	}
+}
+
+func newOpenPGPSigner(armoredKey, passphrase string) (AssetSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenPGP key: %w", err)
+	}
+
+	var signer *openpgp.Entity
+	for _, entity := range entities {
+		if entity.PrivateKey != nil {
+			signer = entity
+			break
+		}
+	}
+	if signer == nil {
+		return nil, fmt.Errorf("the OpenPGP key does not contain a private key")
+	}
+	if err := signer.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+		return nil, fmt.Errorf("failed to decrypt OpenPGP key: %w", err)
+	}
+
+	return func(w io.Writer, message io.Reader, _ string) error {
+		return openpgp.ArmoredDetachSign(w, signer, message, nil)
+	}, nil
+}
+
+func newMinisignSigner(encryptedKey, password string) (AssetSigner, error) {
+	privateKey, err := minisign.DecryptKey(password, []byte(strings.TrimSpace(encryptedKey)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt minisign key: %w", err)
+	}
+
+	return func(w io.Writer, message io.Reader, fileName string) error {
+		reader := minisign.NewReader(message)
+		if _, err := io.Copy(io.Discard, reader); err != nil {
+			return err
+		}
+		trustedComment := fmt.Sprintf("timestamp:%d\tfile:%s", time.Now().Unix(), fileName)
+		untrustedComment := "signature from minisign secret key " + strings.ToUpper(strconv.FormatUint(privateKey.ID(), 16))
+		_, err := w.Write(reader.SignWithComments(privateKey, trustedComment, untrustedComment))
+		return err
+	}, nil
+}
+
+// addSignatureAssets signs every asset and returns the assets extended with the detached signatures written into dir.
+func addSignatureAssets(assets []releaseAsset, signer *Signer, dir string) ([]releaseAsset, error) {
+	var result []releaseAsset
+	for _, asset := range assets {
+		signature := releaseAsset{
+			path:            filepath.Join(dir, fmt.Sprintf("%d-%s%s", len(result), asset.displayFileName, signer.extension)),
+			displayFileName: asset.displayFileName + signer.extension,
+		}
+		if err := signFile(signer, asset, signature.path); err != nil {
+			return nil, err
+		}
+		result = append(result, asset, signature)
+	}
+	return result, nil
+}
+
+func signFile(signer *Signer, asset releaseAsset, signaturePath string) error {
+	message, err := os.Open(asset.path)
+	if err != nil {
+		return fmt.Errorf("failed to open file (%s): %w", asset.path, err)
+	}
+	defer func() {
+		if err := message.Close(); err != nil {
+			log.Warnf("Failed to close file (%s): %s", asset.path, err)
+		}
+	}()
+
+	signature, err := os.OpenFile(signaturePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create signature file (%s): %w", signaturePath, err)
+	}
+	if err := signer.assetSigner(signature, message, asset.displayFileName); err != nil {
+		_ = signature.Close()
+		return fmt.Errorf("failed to sign file (%s): %w", asset.path, err)
+	}
+	if err := signature.Close(); err != nil {
+		return fmt.Errorf("failed to write signature file (%s): %w", signaturePath, err)
+	}
+	return nil
+}
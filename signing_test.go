@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"aead.dev/minisign"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSignatureAssets(t *testing.T) {
+	content := []byte("test file content\n")
+	filePath := filepath.Join(t.TempDir(), "app.apk")
+	require.NoError(t, os.WriteFile(filePath, content, 0600))
+	assets := []releaseAsset{{path: filePath, displayFileName: "my-app.apk"}}
+
+	t.Log("Creates verifiable OpenPGP signatures")
+	{
+		entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+		require.NoError(t, err)
+		var key bytes.Buffer
+		w, err := armor.Encode(&key, openpgp.PrivateKeyType, nil)
+		require.NoError(t, err)
+		require.NoError(t, entity.SerializePrivate(w, nil))
+		require.NoError(t, w.Close())
+
+		signer, err := GetSigner(signingOpenPGP, key.String(), "")
+		require.NoError(t, err)
+		result, err := addSignatureAssets(assets, signer, t.TempDir())
+		require.NoError(t, err)
+		require.Equal(t, []string{"my-app.apk", "my-app.apk.asc"}, assetNames(result))
+
+		signature, err := os.ReadFile(result[1].path)
+		require.NoError(t, err)
+		_, err = openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity}, bytes.NewReader(content), bytes.NewReader(signature), nil)
+		require.NoError(t, err)
+	}
+
+	t.Log("Creates verifiable minisign signatures")
+	{
+		publicKey, privateKey, err := minisign.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		key, err := minisign.EncryptKey("password", privateKey)
+		require.NoError(t, err)
+
+		signer, err := GetSigner(signingMinisign, string(key), "password")
+		require.NoError(t, err)
+		result, err := addSignatureAssets(assets, signer, t.TempDir())
+		require.NoError(t, err)
+		require.Equal(t, []string{"my-app.apk", "my-app.apk.minisig"}, assetNames(result))
+
+		signature, err := os.ReadFile(result[1].path)
+		require.NoError(t, err)
+		require.True(t, minisign.Verify(publicKey, content, signature))
+		require.Contains(t, string(signature), "file:my-app.apk")
+	}
+}
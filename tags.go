@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+// maxTagPeelDepth limits the tag objects followed while peeling a tag, in case of tags of tags.
+const maxTagPeelDepth = 10
+
+// resolveCommitSHA returns the SHA of the commit the given commitish (branch, tag or SHA) points to.
+func resolveCommitSHA(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, commitish string) (string, error) {
+	var sha string
+	if err := policy.do(ctx, func(uint) error {
+		var err error
+		sha, _, err = client.Repositories.GetCommitSHA1(ctx, owner, repo, commitish, "")
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to resolve commit (%s): %w", commitish, err)
+	}
+	return sha, nil
+}
+
+// resolveTagCommit returns the SHA of the commit the tag points to, annotated tags are peeled to their commit.
+// It returns an empty SHA if the tag does not exist.
+func resolveTagCommit(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, tag string) (string, error) {
+	var ref *github.Reference
+	var notFound bool
+	if err := policy.do(ctx, func(uint) error {
+		var resp *github.Response
+		var err error
+		ref, resp, err = client.Git.GetRef(ctx, owner, repo, "tags/"+tag)
+		if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+			notFound = true
+			return nil
+		}
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("failed to get tag (%s): %w", tag, err)
+	}
+	if notFound {
+		return "", nil
+	}
+
+	object := ref.GetObject()
+	for i := 0; object.GetType() == "tag"; i++ {
+		if i == maxTagPeelDepth {
+			return "", fmt.Errorf("failed to peel tag (%s): too many nested tags", tag)
+		}
+
+		var tagObject *github.Tag
+		if err := policy.do(ctx, func(uint) error {
+			var err error
+			tagObject, _, err = client.Git.GetTag(ctx, owner, repo, object.GetSHA())
+			return err
+		}); err != nil {
+			return "", fmt.Errorf("failed to get tag object (%s): %w", object.GetSHA(), err)
+		}
+		object = tagObject.GetObject()
+	}
+	return object.GetSHA(), nil
+}
+
+// createAnnotatedTag creates an annotated tag object on the commit and the ref of the tag.
+// If the tag already exists on the same commit, nothing is created, if it exists on another commit, an error is returned.
+// The tagger is only set if both the name and the email are given, otherwise GitHub uses the authenticated user.
+// The returned bool reports whether the tag was created.
+func createAnnotatedTag(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, tag, commitish, message, taggerName, taggerEmail string) (bool, error) {
+	sha, err := resolveCommitSHA(ctx, client, policy, owner, repo, commitish)
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := resolveTagCommit(ctx, client, policy, owner, repo, tag)
+	if err != nil {
+		return false, err
+	}
+	if existing == sha {
+		log.Printf("Tag %s already exists on %s", tag, sha)
+		return false, nil
+	}
+	if existing != "" {
+		return false, fmt.Errorf("tag (%s) already exists on another commit (%s) than %s (%s)", tag, existing, commitish, sha)
+	}
+
+	if message == "" {
+		message = tag
+	}
+	tagObject := &github.Tag{
+		Tag:     &tag,
+		Message: &message,
+		Object:  &github.GitObject{Type: github.String("commit"), SHA: &sha},
+	}
+	if taggerName != "" && taggerEmail != "" {
+		tagObject.Tagger = &github.CommitAuthor{Name: &taggerName, Email: &taggerEmail, Date: &github.Timestamp{Time: time.Now()}}
+	}
+
+	var created *github.Tag
+	if err := policy.do(ctx, func(uint) error {
+		var err error
+		created, _, err = client.Git.CreateTag(ctx, owner, repo, tagObject)
+		return err
+	}); err != nil {
+		return false, fmt.Errorf("failed to create tag object (%s): %w", tag, err)
+	}
+
+	// Creating the ref is not idempotent, before retrying it is checked whether a failed attempt created it.
+	if err := policy.do(ctx, func(attempt uint) error {
+		if attempt > 0 {
+			existing, err := resolveTagCommit(ctx, client, RetryPolicy{}, owner, repo, tag)
+			if err != nil {
+				return err
+			}
+			if existing == sha {
+				return nil
+			}
+		}
+
+		_, _, err := client.Git.CreateRef(ctx, owner, repo, &github.Reference{
+			Ref:    github.String("refs/tags/" + tag),
+			Object: &github.GitObject{SHA: created.SHA},
+		})
+		return err
+	}); err != nil {
+		return false, fmt.Errorf("failed to create tag ref (%s): %w", tag, err)
+	}
+
+	log.Printf("Annotated tag %s created on %s", tag, sha)
+	return true, nil
+}
+
+// deleteTag deletes the ref of the tag, used to clean up a tag created by the step.
+func deleteTag(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, tag string) error {
+	if err := policy.do(ctx, func(uint) error {
+		_, err := client.Git.DeleteRef(ctx, owner, repo, "tags/"+tag)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to delete tag (%s): %w", tag, err)
+	}
+	return nil
+}
+
+const (
+	tagMismatchFail   = "fail"
+	tagMismatchWarn   = "warn"
+	tagMismatchIgnore = "ignore"
+)
+
+// checkTagCommit compares the commit of an existing tag with the commit of the release,
+// GitHub ignores the commit if the tag already exists.
+func checkTagCommit(ctx context.Context, client *github.Client, policy RetryPolicy, owner, repo, tag, commitish, onMismatch string) error {
+	if onMismatch == tagMismatchIgnore || commitish == "" {
+		return nil
+	}
+
+	existing, err := resolveTagCommit(ctx, client, policy, owner, repo, tag)
+	if err != nil {
+		return err
+	}
+	if existing == "" {
+		return nil
+	}
+
+	sha, err := resolveCommitSHA(ctx, client, policy, owner, repo, commitish)
+	if err != nil {
+		return err
+	}
+	if existing == sha {
+		return nil
+	}
+
+	err = fmt.Errorf("tag (%s) points to %s, not to %s (%s)", tag, existing, commitish, sha)
+	if onMismatch == tagMismatchWarn {
+		log.Warnf("%s, the release is created on the commit of the tag", err)
+		return nil
+	}
+	return err
+}
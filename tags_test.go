@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAnnotatedTag(t *testing.T) {
+	t.Log("Creates the tag object and the ref on the resolved commit")
+	{
+		var tagRequest, refRequest map[string]interface{}
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/commits/master", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("c0ffee"))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/ref/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		mux.HandleFunc("/repos/owner/repo/git/tags", func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&tagRequest))
+			_, _ = w.Write([]byte(`{"sha":"7a9"}`))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&refRequest))
+			_, _ = w.Write([]byte(`{"ref":"refs/tags/1.0.0"}`))
+		})
+		client := setupTestClient(t, mux, nil)
+
+		created, err := createAnnotatedTag(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.0.0", "master", "Release 1.0.0", "Bot", "bot@example.com")
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, "1.0.0", tagRequest["tag"])
+		require.Equal(t, "Release 1.0.0", tagRequest["message"])
+		require.Equal(t, "c0ffee", tagRequest["object"])
+		require.Equal(t, "commit", tagRequest["type"])
+		require.Equal(t, "Bot", tagRequest["tagger"].(map[string]interface{})["name"])
+		require.Equal(t, map[string]interface{}{"ref": "refs/tags/1.0.0", "sha": "7a9"}, refRequest)
+	}
+
+	t.Log("Does not create the ref again if the response of the first attempt was lost")
+	{
+		var refPosts int
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/commits/master", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("c0ffee"))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/ref/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			if refPosts == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"ref":"refs/tags/1.0.0","object":{"type":"tag","sha":"7a9"}}`))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/tags", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"sha":"7a9"}`))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/tags/7a9", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"sha":"7a9","object":{"type":"commit","sha":"c0ffee"}}`))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+			refPosts++
+			w.WriteHeader(http.StatusBadGateway)
+		})
+		client := setupTestClient(t, mux, nil)
+
+		created, err := createAnnotatedTag(context.Background(), client, RetryPolicy{Retries: 2}, "owner", "repo", "1.0.0", "master", "", "", "")
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, 1, refPosts)
+	}
+
+	t.Log("Fails if the annotated tag exists on another commit")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/commits/master", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("c0ffee"))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/ref/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"ref":"refs/tags/1.0.0","object":{"type":"tag","sha":"7a9"}}`))
+		})
+		mux.HandleFunc("/repos/owner/repo/git/tags/7a9", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"sha":"7a9","object":{"type":"commit","sha":"bad"}}`))
+		})
+		client := setupTestClient(t, mux, nil)
+
+		_, err := createAnnotatedTag(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.0.0", "master", "", "", "")
+		require.EqualError(t, err, "tag (1.0.0) already exists on another commit (bad) than master (c0ffee)")
+	}
+}
+
+func TestCheckTagCommit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/commits/master", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("c0ffee"))
+	})
+	mux.HandleFunc("/repos/owner/repo/git/ref/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ref":"refs/tags/1.0.0","object":{"type":"commit","sha":"bad"}}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/git/ref/tags/2.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	client := setupTestClient(t, mux, nil)
+
+	t.Log("Fails or warns if the existing tag points to another commit")
+	{
+		err := checkTagCommit(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.0.0", "master", tagMismatchFail)
+		require.EqualError(t, err, "tag (1.0.0) points to bad, not to master (c0ffee)")
+
+		require.NoError(t, checkTagCommit(context.Background(), client, RetryPolicy{}, "owner", "repo", "1.0.0", "master", tagMismatchWarn))
+	}
+
+	t.Log("Accepts a tag which does not exist yet")
+	{
+		require.NoError(t, checkTagCommit(context.Background(), client, RetryPolicy{}, "owner", "repo", "2.0.0", "master", tagMismatchFail))
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateData is the data the release name and body templates are rendered with,
+// BuildNumber and BuildURL belong to the Bitrise build, GeneratedReleaseNotes is only set if release notes are generated.
+type templateData struct {
+	Tag         string
+	Version     string
+	Major       int
+	Minor       int
+	Patch       int
+	Prerelease  string
+	Build       string
+	Commit      string
+	Date        time.Time
+	BuildNumber string
+	BuildURL    string
+	Env         map[string]string
+	Assets      []templateAsset
+
+	GeneratedReleaseNotes string
+}
+
+// templateAsset is a file to upload as seen by the templates, Size is in bytes.
+type templateAsset struct {
+	Name string
+	Path string
+	Size int64
+}
+
+func newTemplateData(tag, commit string, assets []releaseAsset) (templateData, error) {
+	data := templateData{
+		Tag:         tag,
+		Version:     strings.TrimPrefix(tag, "v"),
+		Commit:      commit,
+		Date:        time.Now(),
+		BuildNumber: os.Getenv("BITRISE_BUILD_NUMBER"),
+		BuildURL:    os.Getenv("BITRISE_BUILD_URL"),
+		Env:         map[string]string{},
+	}
+	for _, asset := range assets {
+		info, err := os.Stat(asset.path)
+		if err != nil {
+			return templateData{}, err
+		}
+		data.Assets = append(data.Assets, templateAsset{Name: asset.displayFileName, Path: asset.path, Size: info.Size()})
+	}
+	if version, err := parseSemanticVersion(tag); err == nil {
+		data.Major, data.Minor, data.Patch = version.Major, version.Minor, version.Patch
+		data.Prerelease, data.Build = version.Prerelease, version.Build
+	}
+	for _, env := range os.Environ() {
+		if key, value, ok := strings.Cut(env, "="); ok {
+			data.Env[key] = value
+		}
+	}
+	return data, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"short": func(sha string) string {
+		if len(sha) > 7 {
+			return sha[:7]
+		}
+		return sha
+	},
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"env":        os.Getenv,
+	"assetTable": assetTable,
+}
+
+// renderTemplate renders the named template, parse and execution errors contain the line number of the failing action.
+func renderTemplate(name, text string, data templateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return rendered.String(), nil
+}
+
+// assetTable returns a Markdown table of the names and sizes of the assets.
+func assetTable(assets []templateAsset) string {
+	var table strings.Builder
+	table.WriteString("| File | Size |\n| --- | --- |\n")
+	for _, asset := range assets {
+		fmt.Fprintf(&table, "| %s | %s |\n", asset.Name, formatFileSize(asset.Size))
+	}
+	return table.String()
+}
+
+func formatFileSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
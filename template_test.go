@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := templateData{
+		Tag:     "v1.2.3-rc.1",
+		Version: "1.2.3-rc.1",
+		Major:   1, Minor: 2, Patch: 3, Prerelease: "rc.1",
+		Commit: "2b6c76430d1e303a9a718a29a93d5d133a353349",
+		Date:   time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+		Env:    map[string]string{"BITRISE_GIT_BRANCH": "main"},
+	}
+
+	t.Log("Renders fields and helper functions")
+	{
+		rendered, err := renderTemplate("name", `Release {{ .Version }} ({{ .Commit | short }}) {{ .Major }}.{{ .Minor }} {{ date "2006-01-02" .Date }} {{ .Env.BITRISE_GIT_BRANCH }}`, data)
+		require.NoError(t, err)
+		require.Equal(t, "Release 1.2.3-rc.1 (2b6c764) 1.2 2024-01-31 main", rendered)
+	}
+
+	t.Log("Renders the assets")
+	{
+		filePath := filepath.Join(t.TempDir(), "app.apk")
+		require.NoError(t, os.WriteFile(filePath, make([]byte, 1536), 0600))
+		assetData, err := newTemplateData("v1.2.3", "", []releaseAsset{{path: filePath, displayFileName: "my-app.apk"}})
+		require.NoError(t, err)
+		data := data
+		data.Assets = assetData.Assets
+
+		rendered, err := renderTemplate("body", `{{ assetTable .Assets }}`, data)
+		require.NoError(t, err)
+		require.Equal(t, "| File | Size |\n| --- | --- |\n| my-app.apk | 1.5 KiB |\n", rendered)
+
+		rendered, err = renderTemplate("body", `{{ range .Assets }}{{ .Name }} {{ .Path }} {{ .Size }}{{ end }}`, data)
+		require.NoError(t, err)
+		require.Equal(t, "my-app.apk "+filePath+" 1536", rendered)
+	}
+
+	t.Log("Reports the line of parse errors")
+	{
+		_, err := renderTemplate("body", "## Notes\n\n{{ .Version | unknown }}", data)
+		require.EqualError(t, err, `invalid body template: template: body:3: function "unknown" not defined`)
+	}
+
+	t.Log("Reports the line of execution errors")
+	{
+		_, err := renderTemplate("body", "## Notes\n{{ .Env.MISSING }}", data)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "template: body:2:")
+	}
+}
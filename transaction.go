@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/google/go-github/v62/github"
+)
+
+// releaseTransaction keeps track of the changes made to a release in transactional mode,
+// so that the release is only made visible once every asset is uploaded, and the changes can be rolled back otherwise.
+type releaseTransaction struct {
+	client      *github.Client
+	retryPolicy RetryPolicy
+	owner, repo string
+	release     *github.RepositoryRelease
+	created     bool
+	uploaded    []*github.ReleaseAsset
+	makeLatest  *string
+	// pending is the edit of an existing release, applied only once every asset is uploaded.
+	pending *github.RepositoryRelease
+	// createdTag is the tag created by the step for the release, deleted on rollback.
+	createdTag string
+}
+
+// commit applies the pending edit of an existing release, or the requested draft state to the release created as a draft.
+// The make_latest value is sent again as GitHub only applies it when the release is published.
+func (t *releaseTransaction) commit(ctx context.Context, draft bool) (*github.RepositoryRelease, error) {
+	edit := &github.RepositoryRelease{Draft: &draft, MakeLatest: t.makeLatest}
+	if t.pending != nil {
+		pending := *t.pending
+		pending.Draft = &draft
+		edit = &pending
+	} else if t.release.GetDraft() == draft {
+		return t.release, nil
+	}
+
+	release, err := editRelease(ctx, t.client, t.retryPolicy, t.owner, t.repo, t.release.GetID(), edit)
+	if err != nil {
+		return nil, err
+	}
+	t.release = release
+	return release, nil
+}
+
+// rollback deletes the release if it was created by the step, otherwise it deletes the assets uploaded to it,
+// the pending edit of an existing release is not applied. The tag created by the step is deleted as well.
+func (t *releaseTransaction) rollback(ctx context.Context) error {
+	var errs []error
+	if t.created {
+		log.Warnf("Deleting release: %s", t.release.GetHTMLURL())
+		if err := t.retryPolicy.do(ctx, func(uint) error {
+			_, err := t.client.Repositories.DeleteRelease(ctx, t.owner, t.repo, t.release.GetID())
+			return err
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete release (%d): %w", t.release.GetID(), err))
+		}
+	} else {
+		for _, asset := range t.uploaded {
+			log.Warnf("Deleting uploaded asset: %s", asset.GetName())
+			if err := deleteReleaseAsset(ctx, t.client, t.retryPolicy, t.owner, t.repo, asset.GetID()); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete asset (%s): %w", asset.GetName(), err))
+			}
+		}
+	}
+
+	if t.createdTag != "" {
+		log.Warnf("Deleting tag: %s", t.createdTag)
+		if err := deleteTag(ctx, t.client, t.retryPolicy, t.owner, t.repo, t.createdTag); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
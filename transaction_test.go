@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseTransaction(t *testing.T) {
+	t.Log("Commit publishes the draft release")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/1", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPatch, r.Method)
+			fmt.Fprint(w, `{"id":1,"draft":false}`)
+		})
+
+		transaction := &releaseTransaction{client: setupTestClient(t, mux, nil), owner: "owner", repo: "repo", release: &github.RepositoryRelease{ID: github.Int64(1), Draft: github.Bool(true)}, created: true}
+		release, err := transaction.commit(context.Background(), false)
+		require.NoError(t, err)
+		require.False(t, release.GetDraft())
+	}
+
+	t.Log("Commit applies the pending edit of an existing release")
+	{
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/1", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPatch, r.Method)
+			var edit github.RepositoryRelease
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&edit))
+			require.Equal(t, "new name", edit.GetName())
+			require.False(t, edit.GetDraft())
+			fmt.Fprint(w, `{"id":1,"name":"new name","draft":false}`)
+		})
+
+		transaction := &releaseTransaction{
+			client:  setupTestClient(t, mux, nil),
+			owner:   "owner",
+			repo:    "repo",
+			release: &github.RepositoryRelease{ID: github.Int64(1), Name: github.String("old name")},
+			pending: &github.RepositoryRelease{Name: github.String("new name")},
+		}
+		release, err := transaction.commit(context.Background(), false)
+		require.NoError(t, err)
+		require.Equal(t, "new name", release.GetName())
+	}
+
+	t.Log("Rollback deletes the created release")
+	{
+		var deleted []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/1", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		transaction := &releaseTransaction{client: setupTestClient(t, mux, nil), owner: "owner", repo: "repo", release: &github.RepositoryRelease{ID: github.Int64(1)}, created: true}
+		require.NoError(t, transaction.rollback(context.Background()))
+		require.Equal(t, []string{"/repos/owner/repo/releases/1"}, deleted)
+	}
+
+	t.Log("Rollback deletes the uploaded assets of an existing release")
+	{
+		var deleted []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/assets/", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		transaction := &releaseTransaction{
+			client:   setupTestClient(t, mux, nil),
+			owner:    "owner",
+			repo:     "repo",
+			release:  &github.RepositoryRelease{ID: github.Int64(1)},
+			uploaded: []*github.ReleaseAsset{{ID: github.Int64(2)}, {ID: github.Int64(3)}},
+		}
+		require.NoError(t, transaction.rollback(context.Background()))
+		require.Equal(t, []string{"/repos/owner/repo/releases/assets/2", "/repos/owner/repo/releases/assets/3"}, deleted)
+	}
+
+	t.Log("Rollback deletes the tag created by the step")
+	{
+		var deleted []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/owner/repo/releases/1", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		})
+		mux.HandleFunc("/repos/owner/repo/git/refs/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		transaction := &releaseTransaction{client: setupTestClient(t, mux, nil), owner: "owner", repo: "repo", release: &github.RepositoryRelease{ID: github.Int64(1)}, created: true, createdTag: "1.0.0"}
+		require.NoError(t, transaction.rollback(context.Background()))
+		require.Equal(t, []string{"/repos/owner/repo/releases/1", "/repos/owner/repo/git/refs/tags/1.0.0"}, deleted)
+	}
+}
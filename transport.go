@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpClientConfig configures the HTTP client shared by the API and the upload requests.
+type httpClientConfig struct {
+	ProxyURL           string
+	CACertificates     string
+	InsecureSkipVerify bool
+	RequestTimeout     time.Duration
+	Deadline           time.Time
+	RateLimitMaxWait   time.Duration
+}
+
+// newHTTPClient returns the HTTP client of the config, zero values keep the defaults of http.DefaultTransport.
+// The request timeout applies to every attempt separately and does not include the time spent waiting for rate limits,
+// after the deadline no more requests are sent.
+func newHTTPClient(config httpClientConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			// The error of url.Parse contains the URL with its credentials.
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL (%s): scheme and host are required", proxyURL.Redacted())
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if config.CACertificates != "" || config.InsecureSkipVerify {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: config.InsecureSkipVerify} // #nosec G402 -- opt-in, marked unsafe
+		if config.CACertificates != "" {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM([]byte(config.CACertificates)) {
+				return nil, errors.New("no valid PEM encoded CA certificate found")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if config.RequestTimeout > 0 {
+		roundTripper = &requestTimeoutTransport{base: roundTripper, timeout: config.RequestTimeout}
+	}
+	roundTripper = newRateLimitTransport(roundTripper, config.RateLimitMaxWait)
+	if !config.Deadline.IsZero() {
+		roundTripper = &deadlineTransport{base: roundTripper, deadline: config.Deadline}
+	}
+	return &http.Client{Transport: roundTripper}, nil
+}
+
+// requestTimeoutTransport cancels a request if it is not finished, including reading the response body, within the timeout.
+type requestTimeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip ...
+func (t *requestTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	return roundTripWithCancel(t.base, req.WithContext(ctx), cancel)
+}
+
+// deadlineTransport cancels the requests in progress at the deadline and refuses to send new ones after it.
+type deadlineTransport struct {
+	base     http.RoundTripper
+	deadline time.Time
+}
+
+// RoundTrip ...
+func (t *deadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !time.Now().Before(t.deadline) {
+		return nil, nonRetryableError{fmt.Errorf("overall timeout exceeded at %s", t.deadline.Format(time.RFC3339))}
+	}
+	ctx, cancel := context.WithDeadline(req.Context(), t.deadline)
+	return roundTripWithCancel(t.base, req.WithContext(ctx), cancel)
+}
+
+// roundTripWithCancel sends the request and calls cancel once the response body is closed.
+func roundTripWithCancel(base http.RoundTripper, req *http.Request, cancel context.CancelFunc) (*http.Response, error) {
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}